@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -18,6 +20,7 @@ import (
 	"github.com/turbot/steampipe/pkg/control/controldisplay"
 	"github.com/turbot/steampipe/pkg/control/controlexecute"
 	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/db/db_local"
 	"github.com/turbot/steampipe/pkg/display"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/statushooks"
@@ -68,7 +71,7 @@ You may specify one or more benchmarks or controls to run (separated by a space)
 		AddStringSliceFlag(constants.ArgSearchPath, nil, "Set a custom search_path for the steampipe user for a check session (comma-separated)").
 		AddStringSliceFlag(constants.ArgSearchPathPrefix, nil, "Set a prefix to the current search path for a check session (comma-separated)").
 		AddStringFlag(constants.ArgTheme, "dark", "Set the output theme for 'text' output: light, dark or plain").
-		AddStringSliceFlag(constants.ArgExport, nil, "Export output to file, supported formats: csv, html, json, md, nunit3, sps (snapshot), asff").
+		AddStringSliceFlag(constants.ArgExport, nil, "Export output to file, supported formats: csv, html, json, junit, md, nunit3, sarif, sps (snapshot), asff").
 		AddBoolFlag(constants.ArgProgress, true, "Display control execution progress").
 		AddBoolFlag(constants.ArgDryRun, false, "Show which controls will be run without running them").
 		AddStringSliceFlag(constants.ArgTag, nil, "Filter controls based on their tag values ('--tag key=value')").
@@ -86,7 +89,10 @@ You may specify one or more benchmarks or controls to run (separated by a space)
 		AddBoolFlag(constants.ArgShare, false, "Create snapshot in Turbot Pipes with 'anyone_with_link' visibility").
 		AddStringArrayFlag(constants.ArgSnapshotTag, nil, "Specify tags to set on the snapshot").
 		AddStringFlag(constants.ArgSnapshotLocation, "", "The location to write snapshots - either a local file path or a Turbot Pipes workspace").
-		AddStringFlag(constants.ArgSnapshotTitle, "", "The title to give a snapshot")
+		AddStringFlag(constants.ArgSnapshotTitle, "", "The title to give a snapshot").
+		AddStringFlag(constants.ArgBaseline, "", "Only report results whose status has changed since the given baseline (a previous check result exported as json)").
+		AddBoolFlag(constants.ArgStoreResults, false, "Store the results in the check history tables of the local database").
+		AddIntFlag(constants.ArgStoreResultsRetention, constants.CheckResultsDefaultRetentionDays, "The number of days of check history to keep when storing results (0 keeps all history)")
 
 	cmd.AddCommand(getListSubCmd(listSubCmdOptions{parentCmd: cmd}))
 	return cmd
@@ -96,6 +102,7 @@ You may specify one or more benchmarks or controls to run (separated by a space)
 // exitCode=1 no runtime errors, 1 or more control alarms, no control errors
 // exitCode=2 no runtime errors, 1 or more control errors
 // exitCode=3+ runtime errors
+// NOTE: if a baseline is specified, only alarms and errors which are new since the baseline are considered
 
 func runCheckCmd(cmd *cobra.Command, args []string) {
 	utils.LogTime("runCheckCmd start")
@@ -152,6 +159,8 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 
 	// pull out useful properties
 	totalAlarms, totalErrors := 0, 0
+	// if a baseline was specified, the exit code depends only on the results which regressed
+	newAlarms, newErrors := 0, 0
 
 	// get the execution trees
 	// depending on the set of arguments and the export targets, we may get more than one
@@ -173,6 +182,16 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 		totalAlarms += namedTree.tree.Root.Summary.Status.Alarm
 		totalErrors += namedTree.tree.Root.Summary.Status.Error
 
+		if initData.Baseline != nil {
+			diff := initData.Baseline.Diff(namedTree.tree)
+			newAlarms += diff.Summary.NewAlarms
+			newErrors += diff.Summary.NewErrors
+			// a failure to display the diff must not prevent the results being published or exported
+			if err = displayControlDiff(ctx, diff, initData.DiffFormatter); err != nil {
+				error_helpers.ShowError(ctx, err)
+			}
+		}
+
 		err = publishSnapshot(ctx, namedTree.tree, viper.GetBool(constants.ArgShare), viper.GetBool(constants.ArgSnapshot))
 		if err != nil {
 			error_helpers.ShowError(ctx, err)
@@ -181,6 +200,13 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 
 		printTiming(namedTree.tree)
 
+		if viper.GetBool(constants.ArgStoreResults) {
+			// a failure to store the results must not prevent them being exported
+			if err = storeResults(ctx, namedTree); err != nil {
+				error_helpers.ShowError(ctx, err)
+			}
+		}
+
 		err = exportExecutionTree(ctx, namedTree, initData, viper.GetStringSlice(constants.ArgExport))
 		if err != nil {
 			error_helpers.ShowError(ctx, err)
@@ -189,7 +215,11 @@ func runCheckCmd(cmd *cobra.Command, args []string) {
 	}
 
 	// set the defined exit code after successful execution
-	exitCode = getExitCode(totalAlarms, totalErrors)
+	if initData.Baseline != nil {
+		exitCode = getExitCode(newAlarms, newErrors)
+	} else {
+		exitCode = getExitCode(totalAlarms, totalErrors)
+	}
 }
 
 // exportExecutionTree relies on the fact that the given tree is already executed
@@ -216,6 +246,27 @@ func exportExecutionTree(ctx context.Context, namedTree *namedExecutionTree, ini
 	return nil
 }
 
+// storeResults writes the results of the given (executed) tree to the check history tables
+func storeResults(ctx context.Context, namedTree *namedExecutionTree) error {
+	if error_helpers.IsContextCanceled(ctx) {
+		return ctx.Err()
+	}
+	// the results are written as the root user, as database users only have read access to the history tables
+	conn, err := db_local.CreateLocalDbConnection(ctx, &db_local.CreateDbOptions{Username: constants.DatabaseSuperUser})
+	if err != nil {
+		return sperr.WrapWithMessage(err, "failed to store check results")
+	}
+	defer conn.Close(ctx)
+
+	retention := time.Duration(viper.GetInt(constants.ArgStoreResultsRetention)) * 24 * time.Hour
+	runId, err := namedTree.tree.StoreResults(ctx, conn, namedTree.name, retention)
+	if err != nil {
+		return sperr.WrapWithMessage(err, "failed to store check results")
+	}
+	log.Printf("[INFO] stored results of %s with run id %s", namedTree.name, runId)
+	return nil
+}
+
 // executeTree executes and displays the (table) results of an execution
 func executeTree(ctx context.Context, tree *controlexecute.ExecutionTree, initData *control.InitData) error {
 	// create a context with check status hooks
@@ -225,6 +276,10 @@ func executeTree(ctx context.Context, tree *controlexecute.ExecutionTree, initDa
 		return err
 	}
 
+	// if there is a baseline, only the differences are displayed (once the tree has been executed)
+	if initData.Baseline != nil {
+		return nil
+	}
 	err = displayControlResults(checkCtx, tree, initData.OutputFormatter)
 	if err != nil {
 		return err
@@ -340,6 +395,12 @@ func validateCheckArgs(ctx context.Context, cmd *cobra.Command, args []string) b
 		return false
 	}
 
+	// the check history tables only exist in the local database
+	if viper.GetBool(constants.ArgStoreResults) && viper.GetString(constants.ArgWorkspaceDatabase) != constants.DefaultWorkspaceDatabase {
+		error_helpers.ShowError(ctx, fmt.Errorf("'--%s' is only supported for the local database", constants.ArgStoreResults))
+		return false
+	}
+
 	// if both '--where' and '--tag' have been used, then it's an error
 	if viper.IsSet(constants.ArgWhere) && viper.IsSet(constants.ArgTag) {
 		error_helpers.ShowError(ctx, fmt.Errorf("only 1 of '--%s' and '--%s' may be set", constants.ArgWhere, constants.ArgTag))
@@ -392,6 +453,15 @@ func displayControlResults(ctx context.Context, executionTree *controlexecute.Ex
 	return err
 }
 
+func displayControlDiff(ctx context.Context, diff *controlexecute.ExecutionTreeDiff, formatter controldisplay.DiffFormatter) error {
+	reader, err := formatter.Format(ctx, diff)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(os.Stdout, reader)
+	return err
+}
+
 type namedExecutionTree struct {
 	tree *controlexecute.ExecutionTree
 	name string
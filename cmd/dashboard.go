@@ -54,6 +54,7 @@ The current mod is the working directory, or the directory specified by the --mo
 		AddStringSliceFlag(constants.ArgSearchPath, nil, "Set a custom search_path for the steampipe user for a dashboard session (comma-separated)").
 		AddStringSliceFlag(constants.ArgSearchPathPrefix, nil, "Set a prefix to the current search path for a dashboard session (comma-separated)").
 		AddIntFlag(constants.ArgMaxParallel, constants.DefaultMaxConnections, "The maximum number of concurrent database connections to open").
+		AddIntFlag(constants.ArgDashboardCacheTtl, constants.DashboardCacheDefaultTtl, "The time in seconds that query results are shared between dashboard sessions (by default results are not shared)").
 		AddStringSliceFlag(constants.ArgVarFile, nil, "Specify an .spvar file containing variable values").
 		AddBoolFlag(constants.ArgProgress, true, "Display dashboard execution progress respected when a dashboard name argument is passed").
 		// NOTE: use StringArrayFlag for ArgVariable, not StringSliceFlag
@@ -69,7 +70,7 @@ The current mod is the working directory, or the directory specified by the --mo
 		// Cobra will interpret values passed to a StringSliceFlag as CSV, where args passed to StringArrayFlag are not parsed and used raw
 		AddStringArrayFlag(constants.ArgDashboardInput, nil, "Specify the value of a dashboard input").
 		AddStringArrayFlag(constants.ArgSnapshotTag, nil, "Specify tags to set on the snapshot").
-		AddStringSliceFlag(constants.ArgExport, nil, "Export output to file, supported format: sps (snapshot)").
+		AddStringSliceFlag(constants.ArgExport, nil, "Export output to file, supported formats: sps (snapshot), csv, json (a file for each table, chart and card panel)").
 		// hidden flags that are used internally
 		AddBoolFlag(constants.ArgServiceMode, false, "Hidden flag to specify whether this is starting as a service", cmdconfig.FlagOptions.Hidden())
 
@@ -269,7 +270,8 @@ func getInitData(ctx context.Context) *initialisation.InitData {
 }
 
 func dashboardExporters() []export.Exporter {
-	return []export.Exporter{&export.SnapshotExporter{}}
+	// the csv and json exporters write the data of each panel to a separate file
+	return append([]export.Exporter{&export.SnapshotExporter{}}, dashboardexecute.PanelDataExporters()...)
 }
 
 func runSingleDashboard(ctx context.Context, targetName string, inputs map[string]interface{}) error {
@@ -401,7 +403,7 @@ func onServerStarted(ctx context.Context, serverPort dashboardserver.ListenPort,
 }
 
 func buildDashboardURL(serverPort dashboardserver.ListenPort, w *workspace.Workspace) string {
-	url := fmt.Sprintf("http://localhost:%d", serverPort)
+	url := fmt.Sprintf("%s://localhost:%d", dashboardserver.URLScheme(), serverPort)
 	if len(w.SourceSnapshots) == 1 {
 		for snapshotName := range w.GetResourceMaps().Snapshots {
 			url += fmt.Sprintf("/%s", snapshotName)
@@ -439,6 +441,7 @@ func saveDashboardState(serverPort dashboardserver.ListenPort, serverListen dash
 		Port:       int(serverPort),
 		ListenType: string(serverListen),
 		Listen:     constants.DashboardListenAddresses,
+		TLS:        dashboardserver.TLSEnabled(),
 	}
 
 	if serverListen == dashboardserver.ListenTypeNetwork {
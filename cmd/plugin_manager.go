@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/turbot/go-kit/helpers"
 	"github.com/turbot/go-kit/logging"
 	"github.com/turbot/go-kit/types"
@@ -30,7 +31,8 @@ func pluginManagerCmd() *cobra.Command {
 		Run:    runPluginManagerCmd,
 		Hidden: true,
 	}
-	cmdconfig.OnCmd(cmd)
+	cmdconfig.OnCmd(cmd).
+		AddStringFlag(constants.ArgMetricsListen, "", "Serve OpenMetrics on this address")
 	return cmd
 }
 
@@ -51,11 +53,21 @@ func runPluginManagerCmd(cmd *cobra.Command, _ []string) {
 }
 
 func doRunPluginManager(cmd *cobra.Command) error {
-	pluginManager, err := createPluginManager(cmd)
+	pluginManager, steampipeConfig, err := createPluginManager(cmd)
 	if err != nil {
 		return err
 	}
 
+	if metricsListen := viper.GetString(constants.ArgMetricsListen); metricsListen != "" {
+		if err := pluginManager.StartMetricsServer(cmd.Context(), metricsListen); err != nil {
+			return err
+		}
+	}
+
+	if err := pluginManager.StartScheduler(steampipeConfig.Schedules); err != nil {
+		return err
+	}
+
 	if shouldRunConnectionWatcher() {
 		log.Printf("[INFO] starting connection watcher")
 		connectionWatcher, err := connection.NewConnectionWatcher(pluginManager)
@@ -72,7 +84,7 @@ func doRunPluginManager(cmd *cobra.Command) error {
 	return nil
 }
 
-func createPluginManager(cmd *cobra.Command) (*pluginmanager_service.PluginManager, error) {
+func createPluginManager(cmd *cobra.Command) (*pluginmanager_service.PluginManager, *steampipeconfig.SteampipeConfig, error) {
 	logger := createPluginManagerLog()
 
 	log.Printf("[INFO] starting plugin manager")
@@ -80,7 +92,7 @@ func createPluginManager(cmd *cobra.Command) (*pluginmanager_service.PluginManag
 	steampipeConfig, errorsAndWarnings := steampipeconfig.LoadConnectionConfig()
 	if errorsAndWarnings.GetError() != nil {
 		log.Printf("[WARN] failed to load connection config: %v", errorsAndWarnings.GetError())
-		return nil, errorsAndWarnings.Error
+		return nil, nil, errorsAndWarnings.Error
 	}
 
 	// add signal handler for sigpipe - this will be raised if we call displayWarning as stdout is piped
@@ -100,10 +112,10 @@ func createPluginManager(cmd *cobra.Command) (*pluginmanager_service.PluginManag
 	pluginManager, err := pluginmanager_service.NewPluginManager(cmd.Context(), configMap, steampipeConfig.PluginsInstances, logger)
 	if err != nil {
 		log.Printf("[WARN] failed to create plugin manager: %s", err.Error())
-		return nil, err
+		return nil, nil, err
 	}
 
-	return pluginManager, nil
+	return pluginManager, steampipeConfig, nil
 }
 
 func shouldRunConnectionWatcher() bool {
@@ -78,9 +78,12 @@ Examples:
 		AddBoolFlag(constants.ArgHelp, false, "Help for query", cmdconfig.FlagOptions.WithShortHand("h")).
 		AddBoolFlag(constants.ArgHeader, true, "Include column headers csv and table output").
 		AddStringFlag(constants.ArgSeparator, ",", "Separator string for csv output").
-		AddStringFlag(constants.ArgOutput, "table", "Output format: line, csv, json, table or snapshot").
+		AddStringFlag(constants.ArgOutput, "table", "Output format: line, csv, json, jsonl, md, html, yaml, table or snapshot").
 		AddBoolFlag(constants.ArgTiming, false, "Turn on the timer which reports query time").
+		AddBoolFlag(constants.ArgExplain, false, "Show the query plan and the rows fetched, hydrate calls and time of each foreign table scan").
 		AddBoolFlag(constants.ArgWatch, true, "Watch SQL files in the current workspace (works only in interactive mode)").
+		AddIntFlag(constants.ArgMaxParallel, 1, "The maximum number of queries to run concurrently (works only in batch mode)").
+		AddIntFlag(constants.ArgMaxRows, 0, "The maximum number of rows to return for a query - larger results are truncated, or in interactive mode you are asked whether to fetch the remaining rows (0 for no limit)").
 		AddStringSliceFlag(constants.ArgSearchPath, nil, "Set a custom search_path for the steampipe user for a query session (comma-separated)").
 		AddStringSliceFlag(constants.ArgSearchPathPrefix, nil, "Set a prefix to the current search path for a query session (comma-separated)").
 		AddStringSliceFlag(constants.ArgVarFile, nil, "Specify a file containing variable values").
@@ -94,7 +97,7 @@ Examples:
 		AddStringArrayFlag(constants.ArgSnapshotTag, nil, "Specify tags to set on the snapshot").
 		AddStringFlag(constants.ArgSnapshotTitle, "", "The title to give a snapshot").
 		AddIntFlag(constants.ArgDatabaseQueryTimeout, 0, "The query timeout").
-		AddStringSliceFlag(constants.ArgExport, nil, "Export output to file, supported format: sps (snapshot)").
+		AddStringSliceFlag(constants.ArgExport, nil, "Export output to file, supported formats: csv, json, jsonl, md, html, yaml, sps (snapshot)").
 		AddStringFlag(constants.ArgSnapshotLocation, "", "The location to write snapshots - either a local file path or a Turbot Pipes workspace").
 		AddBoolFlag(constants.ArgProgress, true, "Display snapshot upload status")
 
@@ -190,7 +193,7 @@ func validateQueryArgs(ctx context.Context, args []string) error {
 		return err
 	}
 
-	validOutputFormats := []string{constants.OutputFormatLine, constants.OutputFormatCSV, constants.OutputFormatTable, constants.OutputFormatJSON, constants.OutputFormatSnapshot, constants.OutputFormatSnapshotShort, constants.OutputFormatNone}
+	validOutputFormats := []string{constants.OutputFormatLine, constants.OutputFormatCSV, constants.OutputFormatTable, constants.OutputFormatJSON, constants.OutputFormatJSONL, constants.OutputFormatMarkdown, constants.OutputFormatHTML, constants.OutputFormatYAML, constants.OutputFormatSnapshot, constants.OutputFormatSnapshotShort, constants.OutputFormatNone}
 	output := viper.GetString(constants.ArgOutput)
 	if !helpers.StringSliceContains(validOutputFormats, output) {
 		exitCode = constants.ExitCodeInsufficientOrWrongInputs
@@ -272,9 +275,17 @@ func executeSnapshotQuery(initData *query.InitData, ctx context.Context) int {
 			}
 
 			// export the result if necessary
-			exportArgs := viper.GetStringSlice(constants.ArgExport)
-			exportMsg, err := initData.ExportManager.DoExport(ctx, snap.FileNameRoot, snap, exportArgs)
+			snapshotExports, resultExports := splitExportArgs(viper.GetStringSlice(constants.ArgExport))
+			exportMsg, err := initData.ExportManager.DoExport(ctx, snap.FileNameRoot, snap, snapshotExports)
 			error_helpers.FailOnErrorWithMessage(err, "failed to export snapshot")
+			// any other exports require the snapshot to be converted into a query result
+			for _, exportArg := range resultExports {
+				result, err := snapshotToQueryResult(snap)
+				error_helpers.FailOnErrorWithMessage(err, "failed to export query result")
+				msg, err := initData.ExportManager.DoExport(ctx, snap.FileNameRoot, result, []string{exportArg})
+				error_helpers.FailOnErrorWithMessage(err, "failed to export query result")
+				exportMsg = append(exportMsg, msg...)
+			}
 			// print the location where the file is exported
 			if len(exportMsg) > 0 && viper.GetBool(constants.ArgProgress) {
 				fmt.Printf("\n")
@@ -348,19 +359,36 @@ func ensureSnapshotQueryResource(name string, resolvedQuery *modconfig.ResolvedQ
 	return q, false
 }
 
+var snapshotFormatNames = []string{constants.OutputFormatSnapshot, constants.OutputFormatSnapshotShort}
+
 func snapshotRequired() bool {
-	SnapshotFormatNames := []string{constants.OutputFormatSnapshot, constants.OutputFormatSnapshotShort}
 	// if a snapshot exporter is specified return true
 	for _, e := range viper.GetStringSlice(constants.ArgExport) {
-		if helpers.StringSliceContains(SnapshotFormatNames, e) || path.Ext(e) == constants.SnapshotExtension {
+		if isSnapshotExport(e) {
 			return true
 		}
 	}
 	// if share/snapshot args are set or output is snapshot, return true
 	return viper.IsSet(constants.ArgShare) ||
 		viper.IsSet(constants.ArgSnapshot) ||
-		helpers.StringSliceContains(SnapshotFormatNames, viper.GetString(constants.ArgOutput))
+		helpers.StringSliceContains(snapshotFormatNames, viper.GetString(constants.ArgOutput))
+
+}
 
+func isSnapshotExport(e string) bool {
+	return helpers.StringSliceContains(snapshotFormatNames, e) || path.Ext(e) == constants.SnapshotExtension
+}
+
+// splitExportArgs separates the snapshot export args from the query result export args
+func splitExportArgs(exportArgs []string) (snapshotExports, resultExports []string) {
+	for _, e := range exportArgs {
+		if isSnapshotExport(e) {
+			snapshotExports = append(snapshotExports, e)
+		} else {
+			resultExports = append(resultExports, e)
+		}
+	}
+	return snapshotExports, resultExports
 }
 
 // getPipedStdinData reads the Standard Input and returns the available data as a string
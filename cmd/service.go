@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"strings"
@@ -73,8 +74,10 @@ connection from any Postgres compatible database client.`,
 		AddBoolFlag(constants.ArgDashboard, false, "Run the dashboard webserver with the service").
 		AddStringFlag(constants.ArgDashboardListen, string(dashboardserver.ListenTypeNetwork), "Accept connections from: local (localhost only) or network (open) (dashboard)").
 		AddIntFlag(constants.ArgDashboardPort, constants.DashboardServerDefaultPort, "Report server port").
+		AddIntFlag(constants.ArgDashboardCacheTtl, constants.DashboardCacheDefaultTtl, "The time in seconds that query results are shared between dashboard sessions (by default results are not shared)").
 		// foreground enables the service to run in the foreground - till exit
 		AddBoolFlag(constants.ArgForeground, false, "Run the service in the foreground").
+		AddStringFlag(constants.ArgMetricsListen, "", "Serve service metrics in OpenMetrics format on this address (e.g. localhost:9193)").
 
 		// flags relevant only if the --dashboard arg is used:
 		AddStringSliceFlag(constants.ArgVarFile, nil, "Specify an .spvar file containing variable values (only applies if '--dashboard' flag is also set)").
@@ -179,6 +182,13 @@ func runServiceStartCmd(cmd *cobra.Command, _ []string) {
 		error_helpers.FailOnError(invoker.IsValid())
 	}
 
+	if metricsListen := viper.GetString(constants.ArgMetricsListen); metricsListen != "" {
+		if _, _, err := net.SplitHostPort(metricsListen); err != nil {
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			error_helpers.FailOnError(sperr.New("invalid metrics listen address '%s' - must be of the form host:port", metricsListen))
+		}
+	}
+
 	startResult, dashboardState, dbServiceStarted := startService(ctx, listenAddresses, port, invoker)
 	alreadyRunning := !dbServiceStarted
 
@@ -221,6 +231,10 @@ func startService(ctx context.Context, listenAddresses []string, port int, invok
 			// although this is an edge case, ideally, we should check for the resolved addresses and give the relevant message
 			error_helpers.FailOnError(sperr.New("service is already running and listening on %s - cannot change listen address while it's running", strings.Join(startResult.DbState.ResolvedListenAddresses, ", ")))
 		}
+		if metricsListen := viper.GetString(constants.ArgMetricsListen); metricsListen != "" && metricsListen != startResult.PluginManagerState.MetricsListen {
+			exitCode = constants.ExitCodeInsufficientOrWrongInputs
+			error_helpers.FailOnError(sperr.New("service is already running - cannot change metrics listen address while it's running"))
+		}
 
 		// convert to being invoked by service
 		startResult.DbState.Invoker = constants.InvokerService
@@ -410,6 +424,10 @@ func restartService(ctx context.Context) (_ *db_local.StartResult, _ *dashboards
 	currentDashboardState, err := dashboardserver.GetDashboardServiceState()
 	error_helpers.FailOnError(err)
 
+	// and the plugin manager state, so we can retain the metrics listen address
+	currentPluginManagerState, err := pluginmanager.LoadState()
+	error_helpers.FailOnError(err)
+
 	// stop db
 	stopStatus, err := db_local.StopServices(ctx, viper.GetBool(constants.ArgForce), constants.InvokerService)
 	if err != nil {
@@ -446,6 +464,8 @@ to force a restart.
 
 	// set the password in 'viper' so that it can be used by 'service start'
 	viper.Set(constants.ArgServicePassword, currentDbState.Password)
+	// likewise the metrics listen address, which is used when starting the plugin manager
+	viper.Set(constants.ArgMetricsListen, currentPluginManagerState.MetricsListen)
 
 	// start db
 	dbStartResult := startServiceAndRefreshConnections(ctx, currentDbState.ResolvedListenAddresses, currentDbState.Port, currentDbState.Invoker)
@@ -742,7 +762,11 @@ Database:
 	dashboardMsg := ""
 
 	if dashboardState != nil {
-		browserUrl := fmt.Sprintf("http://%s:%d/", dashboardState.Listen[0], dashboardState.Port)
+		scheme := "http"
+		if dashboardState.TLS {
+			scheme = "https"
+		}
+		browserUrl := fmt.Sprintf("%s://%s:%d/", scheme, dashboardState.Listen[0], dashboardState.Port)
 		dashboardMsg = fmt.Sprintf(`
 Dashboard:
 
@@ -752,12 +776,22 @@ Dashboard:
 `, strings.Join(dashboardState.Listen, ", "), dashboardState.Port, browserUrl)
 	}
 
+	metricsMsg := ""
+	if pmState != nil && pmState.MetricsListen != "" {
+		metricsMsg = fmt.Sprintf(`
+Metrics:
+
+  URL:  http://%s/metrics
+`, pmState.MetricsListen)
+	}
+
 	if dbState.Invoker == constants.InvokerService {
 		statusMessage = fmt.Sprintf(
-			"%s%s%s%s",
+			"%s%s%s%s%s",
 			prefix,
 			postgresMsg,
 			dashboardMsg,
+			metricsMsg,
 			suffix,
 		)
 	} else {
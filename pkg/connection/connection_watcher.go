@@ -86,6 +86,7 @@ func (w *ConnectionWatcher) handleFileWatcherEvent([]fsnotify.Event) {
 	// (plugin manager cannot reference steampipe config to avoid circular deps)
 	configMap := NewConnectionConfigMap(config.Connections)
 	w.pluginManager.OnConnectionConfigChanged(ctx, configMap, config.PluginsInstances)
+	w.pluginManager.OnScheduleConfigChanged(config.Schedules)
 
 	// The only configurations from GlobalConfig which have
 	// impact during Refresh are Database options and the Connections
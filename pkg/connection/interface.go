@@ -13,6 +13,7 @@ type pluginManager interface {
 	shared.PluginManager
 	OnConnectionConfigChanged(context.Context, ConnectionConfigMap, map[string]*modconfig.Plugin)
 	GetConnectionConfig() ConnectionConfigMap
+	OnScheduleConfigChanged(map[string]*modconfig.Schedule)
 	HandlePluginLimiterChanges(PluginLimiterMap) error
 	Pool() *pgxpool.Pool
 	ShouldFetchRateLimiterDefs() bool
@@ -7,6 +7,7 @@ const (
 	ArgForce                   = "force"
 	ArgAll                     = "all"
 	ArgTiming                  = "timing"
+	ArgExplain                 = "explain"
 	ArgOn                      = "on"
 	ArgOff                     = "off"
 	ArgClear                   = "clear"
@@ -19,8 +20,10 @@ const (
 	ArgDashboardListen         = "dashboard-listen"
 	ArgDashboardPort           = "dashboard-port"
 	ArgDashboardStartTimeout   = "dashboard-start-timeout"
+	ArgDashboardCacheTtl       = "dashboard-cache-ttl"
 	ArgSkipConfig              = "skip-config"
 	ArgForeground              = "foreground"
+	ArgMetricsListen           = "metrics-listen"
 	ArgInvoker                 = "invoker"
 	ArgUpdateCheck             = "update-check"
 	ArgTelemetry               = "telemetry"
@@ -36,6 +39,7 @@ const (
 	ArgProgress                = "progress"
 	ArgExport                  = "export"
 	ArgMaxParallel             = "max-parallel"
+	ArgMaxRows                 = "max-rows"
 	ArgLogLevel                = "log-level"
 	ArgDryRun                  = "dry-run"
 	ArgWhere                   = "where"
@@ -66,6 +70,21 @@ const (
 	ArgDatabaseStartTimeout    = "database-start-timeout"
 	ArgMemoryMaxMb             = "memory-max-mb"
 	ArgMemoryMaxMbPlugin       = "memory-max-mb-plugin"
+	ArgBaseline                = "baseline"
+	ArgStoreResults            = "store-results"
+	ArgStoreResultsRetention   = "store-results-retention"
+)
+
+// dashboard server arguments which are only set from the dashboard options
+const (
+	ArgDashboardAuthTokens          = "dashboard-auth-tokens"
+	ArgDashboardAuthUsers           = "dashboard-auth-users"
+	ArgDashboardAuthProxyHeader     = "dashboard-auth-proxy-header"
+	ArgDashboardAuthTrustedProxies  = "dashboard-auth-trusted-proxies"
+	ArgDashboardCertificateFile     = "dashboard-certificate-file"
+	ArgDashboardKeyFile             = "dashboard-key-file"
+	ArgDashboardGenerateCertificate = "dashboard-generate-certificate"
+	ArgDashboardRefreshInterval     = "dashboard-refresh-interval"
 )
 
 // metaquery mode arguments
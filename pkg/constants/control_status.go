@@ -6,4 +6,6 @@ const (
 	ControlSkip  = "skip"
 	ControlInfo  = "info"
 	ControlError = "error"
+	// ControlSuppressed is the status of an alarm which matches a control exception
+	ControlSuppressed = "suppressed"
 )
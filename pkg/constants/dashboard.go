@@ -12,6 +12,10 @@ var DashboardListenAddresses = []string{"localhost", "127.0.0.1"}
 const (
 	DashboardServerDefaultPort    = 9194
 	DashboardAssetsImageRefFormat = "us-docker.pkg.dev/steampipe/steampipe/assets:%s"
+
+	// DashboardCacheDefaultTtl is the default time in seconds that dashboard query results are shared between sessions
+	// (sharing is opt-in - by default every session runs its own queries)
+	DashboardCacheDefaultTtl = 0
 )
 
 var (
@@ -59,6 +59,15 @@ const (
 	PluginInstanceTable = "steampipe_plugin"
 	PluginColumnTable   = "steampipe_plugin_column"
 
+	// CheckRunTable and CheckResultTable are the tables used to store the history of check runs (if enabled)
+	CheckRunTable    = "steampipe_check_run"
+	CheckResultTable = "steampipe_check_result"
+	// CheckResultsDefaultRetentionDays is the default number of days of check history to keep
+	CheckResultsDefaultRetentionDays = 90
+	// QueryMetricsTable is the table steampipe clients record their queries in, if the service is serving metrics
+	// (the records are consumed by the plugin manager when the metrics are scraped)
+	QueryMetricsTable = "steampipe_query_metrics"
+
 	// LegacyConnectionStateTable is the table used to store steampipe connection state
 	LegacyConnectionStateTable       = "steampipe_connection_state"
 	ConnectionTable                  = "steampipe_connection"
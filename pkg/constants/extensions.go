@@ -11,11 +11,15 @@ const (
 	VariablesExtension     = ".spvars"
 	AutoVariablesExtension = ".auto.spvars"
 	JsonExtension          = ".json"
+	JsonlExtension         = ".jsonl"
 	CsvExtension           = ".csv"
+	HtmlExtension          = ".html"
+	YamlExtension          = ".yaml"
 	TextExtension          = ".txt"
 	SnapshotExtension      = ".sps"
 	TokenExtension         = ".tptt"
 	LegacyTokenExtension   = ".sptt"
+	ExceptionsExtension    = ".spexceptions"
 )
 
 var YamlExtensions = []string{".yml", ".yaml"}
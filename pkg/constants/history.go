@@ -2,6 +2,8 @@ package constants
 
 // Constants for History
 const (
-	HistoryFile = "history.json" // File to store historical data
-	HistorySize = 500            // Number of historical records to store
+	HistoryFile       = "history.jsonl" // File to store historical data
+	LegacyHistoryFile = "history.json"  // File used to store historical data before entries were timestamped
+	HistorySize       = 500             // Number of historical records to load for a workspace
+	HistoryFileSize   = 5000            // Number of historical records to keep in the history file (for all workspaces)
 )
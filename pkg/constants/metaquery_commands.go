@@ -8,6 +8,7 @@ const (
 	CmdTableList        = ".tables"             // List all tables
 	CmdOutput           = ".output"             // Set output mode
 	CmdTiming           = ".timing"             // Toggle query timer
+	CmdExplain          = ".explain"            // Toggle query plan and scan breakdown
 	CmdHeaders          = ".header"             // Toggle headers output
 	CmdSeparator        = ".separator"          // Set the column separator
 	CmdExit             = ".exit"               // Exit the interactive prompt
@@ -22,6 +23,9 @@ const (
 	CmdCache            = ".cache"              // cache control
 	CmdCacheTtl         = ".cache_ttl"          // set cache ttl
 	CmdAutoComplete     = ".autocomplete"       // enable or disable auto complete
+	CmdHistory          = ".history"            // list or re-run query history
+	CmdExport           = ".export"             // export the most recent query result
+	CmdWatch            = ".watch"              // re-run a query at an interval
 )
 
 // ArgFromMetaquery converts a metaquery of form '.header' into the config argument used to set the mode, i.e. 'header'
@@ -3,6 +3,10 @@ package constants
 const (
 	OutputFormatCSV           = "csv"
 	OutputFormatJSON          = "json"
+	OutputFormatJSONL         = "jsonl"
+	OutputFormatMarkdown      = "md"
+	OutputFormatHTML          = "html"
+	OutputFormatYAML          = "yaml"
 	OutputFormatTable         = "table"
 	OutputFormatLine          = "line"
 	OutputFormatNone          = "none"
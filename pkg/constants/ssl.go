@@ -7,4 +7,8 @@ const (
 	ServerCert    = "server.crt"
 	RootCert      = "root.crt"
 	SslConfDir    = "/etc/ssl"
+
+	// the dashboard server certificate generated by steampipe (signed by the root certificate)
+	DashboardServerCert    = "dashboard.crt"
+	DashboardServerCertKey = "dashboard.key"
 )
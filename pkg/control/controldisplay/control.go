@@ -107,9 +107,14 @@ func (r ControlRenderer) Render() string {
 	// now render the results (if any)
 	var resultStrings []string
 	for _, row := range r.run.Rows {
+		reason := row.Reason
+		// for suppressed results, include the justification of the exception
+		if row.Status == constants.ControlSuppressed {
+			reason = fmt.Sprintf("%s (%s)", row.Reason, row.Justification)
+		}
 		resultRenderer := NewResultRenderer(
 			row.Status,
-			row.Reason,
+			reason,
 			row.Dimensions,
 			r.colorGenerator,
 			r.width,
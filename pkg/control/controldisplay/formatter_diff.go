@@ -0,0 +1,127 @@
+package controldisplay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// DiffFormatter formats the differences between the results of an execution tree and a baseline
+type DiffFormatter interface {
+	Format(ctx context.Context, diff *controlexecute.ExecutionTreeDiff) (io.Reader, error)
+	Name() string
+}
+
+// GetDiffFormatter returns the DiffFormatter for the given output format
+func GetDiffFormatter(output string) (DiffFormatter, error) {
+	switch output {
+	case constants.OutputFormatText, constants.OutputFormatBrief:
+		return &TextDiffFormatter{}, nil
+	case constants.OutputFormatJSON:
+		return &JSONDiffFormatter{}, nil
+	case constants.OutputFormatNone:
+		return &NullDiffFormatter{}, nil
+	}
+	return nil, fmt.Errorf("output format '%s' is not supported when comparing with a baseline - must be one of text, brief, json or none", output)
+}
+
+type TextDiffFormatter struct{}
+
+func (f *TextDiffFormatter) Format(_ context.Context, diff *controlexecute.ExecutionTreeDiff) (io.Reader, error) {
+	var sb strings.Builder
+	sb.WriteString("\n")
+
+	sections := []struct {
+		change controlexecute.ResultChange
+		title  string
+	}{
+		{controlexecute.ResultChangeNewError, "New errors"},
+		{controlexecute.ResultChangeNewAlarm, "New alarms"},
+		{controlexecute.ResultChangeResolvedError, "Resolved errors"},
+		{controlexecute.ResultChangeResolvedAlarm, "Resolved alarms"},
+	}
+	for _, section := range sections {
+		var results []*controlexecute.ResultDiff
+		for _, r := range diff.Results {
+			if r.Change == section.change {
+				results = append(results, r)
+			}
+		}
+		if len(results) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s\n", ControlColors.GroupTitle(fmt.Sprintf("%s (%d)", section.title, len(results)))))
+		for _, r := range results {
+			sb.WriteString(fmt.Sprintf("  %s\n", f.renderResult(r)))
+		}
+		sb.WriteString("\n")
+	}
+
+	s := diff.Summary
+	sb.WriteString(fmt.Sprintf("Changes since baseline: %d new %s, %d resolved %s, %d new %s, %d resolved %s\n",
+		s.NewAlarms, utils.Pluralize("alarm", s.NewAlarms),
+		s.ResolvedAlarms, utils.Pluralize("alarm", s.ResolvedAlarms),
+		s.NewErrors, utils.Pluralize("error", s.NewErrors),
+		s.ResolvedErrors, utils.Pluralize("error", s.ResolvedErrors)))
+
+	return strings.NewReader(sb.String()), nil
+}
+
+func (f *TextDiffFormatter) renderResult(r *controlexecute.ResultDiff) string {
+	var sb strings.Builder
+	// a result which is no longer returned has no status
+	if r.Status != "" {
+		sb.WriteString(NewResultStatusRenderer(r.Status).Render())
+	}
+	sb.WriteString(r.ControlId)
+	if r.Resource != "" {
+		sb.WriteString(fmt.Sprintf(" %s", r.Resource))
+	}
+	if r.Reason != "" {
+		sb.WriteString(fmt.Sprintf(": %s", r.Reason))
+	}
+	switch {
+	case r.PreviousStatus == "":
+		sb.WriteString(" (not in baseline)")
+	case r.Status == "":
+		sb.WriteString(fmt.Sprintf(" (was %s, no longer returned)", r.PreviousStatus))
+	default:
+		sb.WriteString(fmt.Sprintf(" (was %s)", r.PreviousStatus))
+	}
+	return sb.String()
+}
+
+func (f *TextDiffFormatter) Name() string {
+	return constants.OutputFormatText
+}
+
+type JSONDiffFormatter struct{}
+
+func (f *JSONDiffFormatter) Format(_ context.Context, diff *controlexecute.ExecutionTreeDiff) (io.Reader, error) {
+	jsonBytes, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(fmt.Sprintf("%s\n", string(jsonBytes))), nil
+}
+
+func (f *JSONDiffFormatter) Name() string {
+	return constants.OutputFormatJSON
+}
+
+// NullDiffFormatter is to be used when no output is expected
+type NullDiffFormatter struct{}
+
+func (f *NullDiffFormatter) Format(context.Context, *controlexecute.ExecutionTreeDiff) (io.Reader, error) {
+	return strings.NewReader(""), nil
+}
+
+func (f *NullDiffFormatter) Name() string {
+	return constants.OutputFormatNone
+}
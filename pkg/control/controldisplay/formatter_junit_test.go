@@ -0,0 +1,123 @@
+package controldisplay
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+)
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name     string          `xml:"name,attr"`
+	Failures []*junitMessage `xml:"failure"`
+	Errors   []*junitMessage `xml:"error"`
+	Skipped  []*junitMessage `xml:"skipped"`
+}
+
+type junitTestSuites struct {
+	Tests    int `xml:"tests,attr"`
+	Failures int `xml:"failures,attr"`
+	Errors   int `xml:"errors,attr"`
+	Skipped  int `xml:"skipped,attr"`
+	Suites   []struct {
+		Failures  int              `xml:"failures,attr"`
+		Errors    int              `xml:"errors,attr"`
+		Skipped   int              `xml:"skipped,attr"`
+		TestCases []*junitTestCase `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+func TestJUnitTemplate(t *testing.T) {
+	output := renderTemplate(t, "junit.xml", testRenderTree(t))
+
+	var res junitTestSuites
+	if err := xml.Unmarshal(output, &res); err != nil {
+		t.Fatalf("failed to parse junit output: %v\n%s", err, output)
+	}
+	if res.Tests != 4 || res.Failures != 1 || res.Errors != 1 || res.Skipped != 1 {
+		t.Errorf("unexpected testsuites counts: tests=%d failures=%d errors=%d skipped=%d", res.Tests, res.Failures, res.Errors, res.Skipped)
+	}
+	if len(res.Suites) != 1 {
+		t.Fatalf("expected 1 testsuite, got %d", len(res.Suites))
+	}
+	suite := res.Suites[0]
+	if suite.Failures != 1 || suite.Errors != 1 || suite.Skipped != 1 {
+		t.Errorf("unexpected testsuite counts: failures=%d errors=%d skipped=%d", suite.Failures, suite.Errors, suite.Skipped)
+	}
+
+	testCases := make(map[string]*junitTestCase)
+	for _, tc := range suite.TestCases {
+		testCases[tc.Name] = tc
+	}
+	if len(testCases) != 4 {
+		t.Fatalf("expected 4 testcases, got %d", len(testCases))
+	}
+
+	// a single failure, listing the failing rows (escaped)
+	alarm := testCases["mod.control.alarm"]
+	if len(alarm.Failures) != 1 || len(alarm.Errors) != 0 {
+		t.Fatalf("expected 1 failure for the alarm control, got %d failures and %d errors", len(alarm.Failures), len(alarm.Errors))
+	}
+	if lines := strings.Split(strings.TrimSpace(alarm.Failures[0].Body), "\n"); len(lines) != 2 || lines[0] != "alarm: arn:a: a is <public>" {
+		t.Errorf("unexpected failure body:\n%s", alarm.Failures[0].Body)
+	}
+	if alarm.Failures[0].Message != "2 of 3 resources failed" {
+		t.Errorf("unexpected failure message: %s", alarm.Failures[0].Message)
+	}
+
+	// run errors are reported as errors rather than failures
+	errored := testCases["mod.control.error"]
+	if len(errored.Errors) != 1 || len(errored.Failures) != 0 || errored.Errors[0].Message != "table not found" {
+		t.Errorf("expected 1 error for the errored control, got %d errors and %d failures", len(errored.Errors), len(errored.Failures))
+	}
+
+	if skipped := testCases["mod.control.skip"]; len(skipped.Skipped) != 1 || len(skipped.Failures) != 0 {
+		t.Errorf("expected the skipped control to be skipped")
+	}
+	if ok := testCases["mod.control.ok"]; len(ok.Failures)+len(ok.Errors)+len(ok.Skipped) != 0 {
+		t.Errorf("expected the passing control to have no failures, errors or skips")
+	}
+}
+
+func TestJUnitTemplateSkipped(t *testing.T) {
+	tree := testRenderTree(t)
+	runs := make(map[string]*controlexecute.ControlRun)
+	for _, run := range tree.ControlRuns {
+		runs[run.ControlId] = run
+	}
+	// a control with some skipped rows is only skipped if none of its rows passed
+	ok := runs["mod.control.ok"]
+	ok.Summary.Skip = 1
+	ok.Rows = append(ok.Rows, &controlexecute.ResultRow{Resource: "arn:f", Status: "skip", Reason: "not applicable", Run: ok, Control: ok.Control})
+	// a control which was not run (e.g. a dry run) has no rows
+	notRun := runs["mod.control.skip"]
+	notRun.Summary = &controlstatus.StatusSummary{}
+	notRun.Rows = nil
+
+	output := renderTemplate(t, "junit.xml", tree)
+
+	var res junitTestSuites
+	if err := xml.Unmarshal(output, &res); err != nil {
+		t.Fatalf("failed to parse junit output: %v\n%s", err, output)
+	}
+	if res.Skipped != 1 || len(res.Suites) != 1 || res.Suites[0].Skipped != 1 {
+		t.Fatalf("expected 1 skipped testcase, got %d\n%s", res.Skipped, output)
+	}
+	testCases := make(map[string]*junitTestCase)
+	for _, tc := range res.Suites[0].TestCases {
+		testCases[tc.Name] = tc
+	}
+	if tc := testCases["mod.control.ok"]; len(tc.Failures)+len(tc.Errors)+len(tc.Skipped) != 0 {
+		t.Errorf("expected the partially skipped control to pass")
+	}
+	if tc := testCases["mod.control.skip"]; len(tc.Skipped) != 1 || tc.Skipped[0].Message != "no resources were checked" {
+		t.Errorf("expected the control with no rows to be skipped")
+	}
+}
@@ -0,0 +1,92 @@
+package controldisplay
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+type sarifLog struct {
+	Runs []struct {
+		OriginalUriBaseIds map[string]struct {
+			Uri string `json:"uri"`
+		} `json:"originalUriBaseIds"`
+		Invocations []struct {
+			ExecutionSuccessful        bool `json:"executionSuccessful"`
+			ToolExecutionNotifications []struct {
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				AssociatedRule struct {
+					Id string `json:"id"`
+				} `json:"associatedRule"`
+			} `json:"toolExecutionNotifications"`
+		} `json:"invocations"`
+		Results []struct {
+			RuleId    string `json:"ruleId"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						Uri       string `json:"uri"`
+						UriBaseId string `json:"uriBaseId"`
+					} `json:"artifactLocation"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			PartialFingerprints map[string]string `json:"partialFingerprints"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func TestSarifTemplate(t *testing.T) {
+	output := renderTemplate(t, "sarif", testRenderTree(t))
+
+	// validate against the SARIF schema
+	schema, err := jsonschema.Compile("testdata/sarif-schema-2.1.0.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc any
+	if err := json.Unmarshal(output, &doc); err != nil {
+		t.Fatalf("failed to parse sarif output: %v\n%s", err, output)
+	}
+	if err := schema.Validate(doc); err != nil {
+		t.Fatalf("sarif output is not valid: %#v\n%s", err, output)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(output, &log); err != nil {
+		t.Fatal(err)
+	}
+	run := log.Runs[0]
+
+	// the control which failed to run is reported as a tool execution notification
+	if len(run.Invocations) != 1 || run.Invocations[0].ExecutionSuccessful {
+		t.Fatalf("expected 1 unsuccessful invocation")
+	}
+	notifications := run.Invocations[0].ToolExecutionNotifications
+	if len(notifications) != 1 || notifications[0].AssociatedRule.Id != "mod.control.error" || notifications[0].Message.Text != "table not found" {
+		t.Errorf("expected a notification for the run error of mod.control.error, got %+v", notifications)
+	}
+
+	// artifact locations are relative to the working directory
+	if _, ok := run.OriginalUriBaseIds["%SRCROOT%"]; !ok {
+		t.Errorf("expected %%SRCROOT%% to be defined in originalUriBaseIds")
+	}
+	fingerprints := make(map[string]bool)
+	for _, result := range run.Results {
+		location := result.Locations[0].PhysicalLocation.ArtifactLocation
+		if location.Uri != "controls.sp" || location.UriBaseId != "%SRCROOT%" {
+			t.Errorf("expected location controls.sp relative to %%SRCROOT%%, got %s relative to '%s'", location.Uri, location.UriBaseId)
+		}
+		fingerprint := result.PartialFingerprints["steampipeResource/v2"]
+		if fingerprints[fingerprint] {
+			t.Errorf("duplicate fingerprint %s", fingerprint)
+		}
+		fingerprints[fingerprint] = true
+	}
+	// each row is a result - including the rows for the same resource in different regions
+	if len(run.Results) != 6 {
+		t.Errorf("expected 6 results, got %d", len(run.Results))
+	}
+}
@@ -0,0 +1,85 @@
+package controldisplay
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// renderTemplate renders the given check output template (from the embedded templates directory) for the tree
+func renderTemplate(t *testing.T, templateName string, tree *controlexecute.ExecutionTree) []byte {
+	formatter, err := NewTemplateFormatter(NewOutputTemplate(filepath.Join("templates", templateName)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := formatter.Format(context.Background(), tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+// testRenderTree returns an executed tree with a control which alarms, one which errors, one which is skipped
+// (with a suppressed alarm) and one which passes
+func testRenderTree(t *testing.T) *controlexecute.ExecutionTree {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod := modconfig.NewMod("mod", workingDir, hcl.Range{})
+	group := &controlexecute.ResultGroup{GroupId: "mod.benchmark.b1", Title: "Benchmark 1"}
+	newRun := func(name string, summary controlstatus.StatusSummary, rows ...*controlexecute.ResultRow) *controlexecute.ControlRun {
+		shortName := strings.TrimPrefix(name, "mod.control.")
+		block := &hcl.Block{
+			Type:     "control",
+			Labels:   []string{shortName},
+			DefRange: hcl.Range{Filename: filepath.Join(workingDir, "controls.sp"), Start: hcl.Pos{Line: 10}},
+		}
+		run := &controlexecute.ControlRun{
+			ControlId: name,
+			FullName:  name,
+			Title:     name + " title",
+			Severity:  "high",
+			Summary:   &summary,
+			Group:     group,
+			Rows:      rows,
+			Control:   modconfig.NewControl(block, mod, shortName).(*modconfig.Control),
+		}
+		for _, row := range rows {
+			row.Run = run
+			row.Control = run.Control
+		}
+		return run
+	}
+	alarm := newRun("mod.control.alarm", controlstatus.StatusSummary{Alarm: 2, Ok: 1},
+		&controlexecute.ResultRow{Resource: "arn:a", Status: "alarm", Reason: "a is <public>", Dimensions: []controlexecute.Dimension{{Key: "region", Value: "us-east-1"}}},
+		&controlexecute.ResultRow{Resource: "arn:a", Status: "alarm", Reason: "a is <public>", Dimensions: []controlexecute.Dimension{{Key: "region", Value: "us-west-2"}}},
+		&controlexecute.ResultRow{Resource: "arn:b", Status: "ok", Reason: "b is private"},
+	)
+	errored := newRun("mod.control.error", controlstatus.StatusSummary{Error: 1})
+	errored.RunErrorString = "table not found"
+	skipped := newRun("mod.control.skip", controlstatus.StatusSummary{Skip: 1, Suppressed: 1},
+		&controlexecute.ResultRow{Resource: "arn:c", Status: "skip", Reason: "not applicable"},
+		&controlexecute.ResultRow{Resource: "arn:e", Status: "suppressed", Reason: "e is public", Exception: "mod.control_exception.e", Justification: "e is a website"},
+	)
+	ok := newRun("mod.control.ok", controlstatus.StatusSummary{Ok: 1},
+		&controlexecute.ResultRow{Resource: "arn:d", Status: "ok", Reason: "d is private"},
+	)
+	group.ControlRuns = []*controlexecute.ControlRun{alarm, errored, skipped, ok}
+	return &controlexecute.ExecutionTree{
+		Root:        &controlexecute.ResultGroup{GroupId: "root", Groups: []*controlexecute.ResultGroup{group}},
+		ControlRuns: group.ControlRuns,
+	}
+}
@@ -0,0 +1,152 @@
+package controlexecute
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// BaselineResult is a single control result read from a baseline file
+type BaselineResult struct {
+	ControlId  string
+	Resource   string
+	Status     string
+	Reason     string
+	Dimensions []Dimension
+}
+
+// Baseline is the set of control results from a previous check run, keyed by control, resource and dimensions
+// it is used to determine which results have changed in the current run
+type Baseline struct {
+	results map[string]*BaselineResult
+}
+
+// the structure of the result groups written by the 'json' check export template
+type baselineResultGroup struct {
+	Groups   []*baselineResultGroup `json:"groups"`
+	Controls []*baselineControlRun  `json:"controls"`
+}
+
+type baselineControlRun struct {
+	ControlId string `json:"control_id"`
+	RunError  string `json:"run_error"`
+	Results   []*struct {
+		Reason     string      `json:"reason"`
+		Resource   string      `json:"resource"`
+		Status     string      `json:"status"`
+		Dimensions []Dimension `json:"dimensions"`
+	} `json:"results"`
+}
+
+// LoadBaseline reads a baseline from the output of a previous 'steampipe check --export json'
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to read baseline file %s", path)
+	}
+	var root baselineResultGroup
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, sperr.WrapWithMessage(err, "failed to parse baseline file %s - baseline must be a check result exported in json format", path)
+	}
+
+	b := &Baseline{results: make(map[string]*BaselineResult)}
+	b.addGroup(&root)
+	return b, nil
+}
+
+func (b *Baseline) addGroup(group *baselineResultGroup) {
+	for _, c := range group.Controls {
+		if c.RunError != "" {
+			b.addResult(&BaselineResult{ControlId: c.ControlId, Status: constants.ControlError, Reason: c.RunError})
+		}
+		for _, r := range c.Results {
+			b.addResult(&BaselineResult{
+				ControlId:  c.ControlId,
+				Resource:   r.Resource,
+				Status:     r.Status,
+				Reason:     r.Reason,
+				Dimensions: r.Dimensions,
+			})
+		}
+	}
+	for _, child := range group.Groups {
+		b.addGroup(child)
+	}
+}
+
+func (b *Baseline) addResult(r *BaselineResult) {
+	b.results[resultKey(r.ControlId, r.Resource, r.Dimensions)] = r
+}
+
+// Diff compares the results of the given (executed) execution tree with the baseline
+// and returns the results whose status has changed
+func (b *Baseline) Diff(tree *ExecutionTree) *ExecutionTreeDiff {
+	diff := &ExecutionTreeDiff{}
+
+	// keep track of the baseline results which are still present
+	matched := make(map[string]bool)
+	// keep track of the controls which ran successfully - only these can have resolved results
+	// (the results of controls which were not run, or which failed, are unknown)
+	completedControls := make(map[string]bool)
+	for _, run := range tree.ControlRuns {
+		if run.RunErrorString == "" {
+			completedControls[run.ControlId] = true
+		}
+		current := controlRunResults(run)
+		for _, r := range current {
+			key := resultKey(r.ControlId, r.Resource, r.Dimensions)
+			matched[key] = true
+
+			previousStatus := ""
+			if previous, ok := b.results[key]; ok {
+				previousStatus = previous.Status
+			}
+			diff.add(r, previousStatus, r.Status)
+		}
+	}
+
+	// now add all baseline results of the completed controls which are no longer present
+	for key, r := range b.results {
+		if !matched[key] && completedControls[r.ControlId] {
+			diff.add(r, r.Status, "")
+		}
+	}
+
+	diff.sort()
+	return diff
+}
+
+// convert the rows (or run error) of a control run into BaselineResults so they can be compared with the baseline
+// if the control failed, a single error result is returned for the control
+func controlRunResults(run *ControlRun) []*BaselineResult {
+	if run.RunErrorString != "" {
+		return []*BaselineResult{{ControlId: run.ControlId, Status: constants.ControlError, Reason: run.RunErrorString}}
+	}
+	res := make([]*BaselineResult, len(run.Rows))
+	for i, row := range run.Rows {
+		res[i] = &BaselineResult{
+			ControlId:  run.ControlId,
+			Resource:   row.Resource,
+			Status:     row.Status,
+			Reason:     row.Reason,
+			Dimensions: row.Dimensions,
+		}
+	}
+	return res
+}
+
+// resultKey builds the key used to match a result with a result from the baseline
+// dimensions are sorted by key, so the key does not depend on the column order of the control query
+func resultKey(controlId, resource string, dimensions []Dimension) string {
+	dimensionStrings := make([]string, len(dimensions))
+	for i, d := range dimensions {
+		dimensionStrings[i] = fmt.Sprintf("%s=%s", d.Key, d.Value)
+	}
+	sort.Strings(dimensionStrings)
+	return strings.Join(append([]string{controlId, resource}, dimensionStrings...), "\x00")
+}
@@ -0,0 +1,118 @@
+package controlexecute
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testBaselineJson = `{
+	"group_id": "root_result_group",
+	"groups": [
+		{
+			"group_id": "benchmark.b1",
+			"groups": [],
+			"controls": [
+				{
+					"control_id": "control.c1",
+					"results": [
+						{"reason": "bucket a is not versioned", "resource": "a", "status": "alarm", "dimensions": [{"key": "region", "value": "us-east-1"}]},
+						{"reason": "bucket b is versioned", "resource": "b", "status": "ok", "dimensions": [{"key": "region", "value": "us-east-1"}]},
+						{"reason": "bucket c is not versioned", "resource": "c", "status": "alarm", "dimensions": null}
+					],
+					"run_error": ""
+				},
+				{
+					"control_id": "control.c2",
+					"results": null,
+					"run_error": "table not found"
+				},
+				{
+					"control_id": "control.c3",
+					"results": [
+						{"reason": "bucket f is not encrypted", "resource": "f", "status": "alarm", "dimensions": null}
+					],
+					"run_error": ""
+				},
+				{
+					"control_id": "control.c4",
+					"results": [
+						{"reason": "bucket g is public", "resource": "g", "status": "alarm", "dimensions": null}
+					],
+					"run_error": ""
+				}
+			]
+		}
+	],
+	"controls": null
+}`
+
+func TestBaselineDiff(t *testing.T) {
+	baselinePath := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(baselinePath, []byte(testBaselineJson), 0600); err != nil {
+		t.Fatal(err)
+	}
+	baseline, err := LoadBaseline(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	region := []Dimension{{Key: "region", Value: "us-east-1"}}
+	tree := &ExecutionTree{
+		ControlRuns: []*ControlRun{
+			{
+				ControlId: "control.c1",
+				Rows: ResultRows{
+					// unchanged alarm
+					{Resource: "a", Status: "alarm", Dimensions: region},
+					// new alarm
+					{Resource: "b", Status: "alarm", Dimensions: region},
+					// resource c is no longer returned - resolved alarm
+					// new resource in error
+					{Resource: "d", Status: "error"},
+					// new resource which is ok - not a change
+					{Resource: "e", Status: "ok"},
+				},
+			},
+			{
+				// run error has been fixed - resolved error
+				ControlId: "control.c2",
+				Rows:      ResultRows{{Resource: "x", Status: "ok"}},
+			},
+			// control.c3 was not run - its results are unknown so are not resolved
+			{
+				// control now fails - new error, but its alarm is not resolved
+				ControlId:      "control.c4",
+				RunErrorString: "connection refused",
+			},
+		},
+	}
+
+	diff := baseline.Diff(tree)
+
+	expectedSummary := ExecutionTreeDiffSummary{NewAlarms: 1, ResolvedAlarms: 1, NewErrors: 2, ResolvedErrors: 1}
+	if diff.Summary != expectedSummary {
+		t.Errorf("expected summary %+v, got %+v", expectedSummary, diff.Summary)
+	}
+	if diff.Regressions() != 3 {
+		t.Errorf("expected 3 regressions, got %d", diff.Regressions())
+	}
+
+	// keyed by control and resource
+	expectedChanges := map[string]ResultChange{
+		"control.c1/b": ResultChangeNewAlarm,
+		"control.c1/c": ResultChangeResolvedAlarm,
+		"control.c1/d": ResultChangeNewError,
+		"control.c2/":  ResultChangeResolvedError,
+		"control.c4/":  ResultChangeNewError,
+	}
+	if len(diff.Results) != len(expectedChanges) {
+		t.Fatalf("expected %d results, got %d", len(expectedChanges), len(diff.Results))
+	}
+	for _, r := range diff.Results {
+		key := r.ControlId + "/" + r.Resource
+		if expected, ok := expectedChanges[key]; !ok || r.Change != expected {
+			t.Errorf("result '%s': expected change '%s', got '%s'", key, expected, r.Change)
+		}
+	}
+}
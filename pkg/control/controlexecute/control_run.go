@@ -341,6 +341,7 @@ func (r *ControlRun) waitForResults(ctx context.Context) {
 				r.setError(ctx, err)
 				return
 			}
+			r.applyException(result)
 			r.addResultRow(result)
 		case <-r.doneChan:
 			return
@@ -369,6 +370,20 @@ func (r *ControlRun) getDimensionSchema() map[string]*queryresult.ColumnDef {
 	return dimensionsSchema
 }
 
+// if the row is an alarm which matches a control exception, set its status to suppressed
+func (r *ControlRun) applyException(row *ResultRow) {
+	if row.Status != constants.ControlAlarm || r.Tree.Workspace == nil {
+		return
+	}
+	exception := r.Tree.Workspace.ControlExceptions.Find(r.Control, row.Resource, time.Now())
+	if exception == nil {
+		return
+	}
+	row.Status = constants.ControlSuppressed
+	row.Exception = exception.Name
+	row.Justification = exception.Justification
+}
+
 // add the result row to our results and update the summary with the row status
 func (r *ControlRun) addResultRow(row *ResultRow) {
 	// update results
@@ -386,12 +401,14 @@ func (r *ControlRun) addResultRow(row *ResultRow) {
 		r.Summary.Info++
 	case constants.ControlError:
 		r.Summary.Error++
+	case constants.ControlSuppressed:
+		r.Summary.Suppressed++
 	}
 }
 
 // populate ordered list of rows
 func (r *ControlRun) createdOrderedResultRows() {
-	statusOrder := []string{constants.ControlError, constants.ControlAlarm, constants.ControlInfo, constants.ControlOk, constants.ControlSkip}
+	statusOrder := []string{constants.ControlError, constants.ControlAlarm, constants.ControlSuppressed, constants.ControlInfo, constants.ControlOk, constants.ControlSkip}
 	for _, status := range statusOrder {
 		r.Rows = append(r.Rows, r.rowMap[status]...)
 	}
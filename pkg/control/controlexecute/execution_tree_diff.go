@@ -0,0 +1,103 @@
+package controlexecute
+
+import (
+	"sort"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+type ResultChange string
+
+const (
+	ResultChangeNewAlarm      ResultChange = "new_alarm"
+	ResultChangeResolvedAlarm ResultChange = "resolved_alarm"
+	ResultChangeNewError      ResultChange = "new_error"
+	ResultChangeResolvedError ResultChange = "resolved_error"
+)
+
+// ResultDiff is a control result whose status differs from the baseline
+type ResultDiff struct {
+	ControlId  string       `json:"control_id"`
+	Resource   string       `json:"resource"`
+	Dimensions []Dimension  `json:"dimensions"`
+	Reason     string       `json:"reason"`
+	Change     ResultChange `json:"change"`
+	// the status in the baseline - empty if the result is not in the baseline
+	PreviousStatus string `json:"previous_status"`
+	// the current status - empty if the result is no longer returned
+	Status string `json:"status"`
+}
+
+type ExecutionTreeDiffSummary struct {
+	NewAlarms      int `json:"new_alarms"`
+	ResolvedAlarms int `json:"resolved_alarms"`
+	NewErrors      int `json:"new_errors"`
+	ResolvedErrors int `json:"resolved_errors"`
+}
+
+// ExecutionTreeDiff is the set of control results which have changed between a baseline and an execution tree
+// only changes into or out of the alarm and error states are included
+type ExecutionTreeDiff struct {
+	Summary ExecutionTreeDiffSummary `json:"summary"`
+	Results []*ResultDiff            `json:"results"`
+}
+
+// Regressions returns the number of results which are newly in alarm or error
+func (d *ExecutionTreeDiff) Regressions() int {
+	return d.Summary.NewAlarms + d.Summary.NewErrors
+}
+
+func (d *ExecutionTreeDiff) add(r *BaselineResult, previousStatus, status string) {
+	change, ok := getResultChange(previousStatus, status)
+	if !ok {
+		return
+	}
+	switch change {
+	case ResultChangeNewAlarm:
+		d.Summary.NewAlarms++
+	case ResultChangeResolvedAlarm:
+		d.Summary.ResolvedAlarms++
+	case ResultChangeNewError:
+		d.Summary.NewErrors++
+	case ResultChangeResolvedError:
+		d.Summary.ResolvedErrors++
+	}
+	d.Results = append(d.Results, &ResultDiff{
+		ControlId:      r.ControlId,
+		Resource:       r.Resource,
+		Dimensions:     r.Dimensions,
+		Reason:         r.Reason,
+		Change:         change,
+		PreviousStatus: previousStatus,
+		Status:         status,
+	})
+}
+
+// sort the results by control then resource, so the output is stable
+func (d *ExecutionTreeDiff) sort() {
+	sort.SliceStable(d.Results, func(i, j int) bool {
+		if d.Results[i].ControlId != d.Results[j].ControlId {
+			return d.Results[i].ControlId < d.Results[j].ControlId
+		}
+		return d.Results[i].Resource < d.Results[j].Resource
+	})
+}
+
+// getResultChange determines the change between two statuses
+// returns false if the change does not move a result into or out of alarm or error
+func getResultChange(previousStatus, status string) (ResultChange, bool) {
+	if previousStatus == status {
+		return "", false
+	}
+	switch {
+	case status == constants.ControlError:
+		return ResultChangeNewError, true
+	case status == constants.ControlAlarm:
+		return ResultChangeNewAlarm, true
+	case previousStatus == constants.ControlError:
+		return ResultChangeResolvedError, true
+	case previousStatus == constants.ControlAlarm:
+		return ResultChangeResolvedAlarm, true
+	}
+	return "", false
+}
@@ -0,0 +1,86 @@
+package controlexecute
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/introspection"
+)
+
+// StoreResults writes the results of the (executed) tree to the check history tables in the local database,
+// returning the id of the stored run
+// conn must be a root connection to the local database - database users can only read the history
+// if retention is non-zero, runs which started more than retention ago are deleted
+func (e *ExecutionTree) StoreResults(ctx context.Context, conn *pgx.Conn, target string, retention time.Duration) (string, error) {
+	runId := uuid.New().String()
+	queries := e.storeResultsQueries(runId, target, e.Workspace.Mod.Name(), retention)
+
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, q := range queries {
+			batch.Queue(q.Query, q.Args...)
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return "", err
+	}
+	return runId, nil
+}
+
+// storeResultsQueries returns the queries to store the results of the tree with the given run id
+// the history tables are created if needed, as the service may have been started by an older version
+func (e *ExecutionTree) storeResultsQueries(runId, target, modName string, retention time.Duration) []db_common.QueryWithArgs {
+	queries := introspection.GetCheckResultTablesCreateSql()
+	queries = append(queries, introspection.GetCheckResultTablesGrantSql()...)
+	if retention > 0 {
+		queries = append(queries, introspection.GetCheckRunDeleteSql(e.StartTime.Add(-retention)))
+	}
+	queries = append(queries, introspection.GetCheckRunInsertSql(runId, target, modName, e.StartTime, e.EndTime, &e.Root.Summary.Status))
+	for _, run := range e.ControlRuns {
+		for _, result := range e.checkResultsForRun(run) {
+			queries = append(queries, introspection.GetCheckResultInsertSql(runId, e.StartTime, result))
+		}
+	}
+	return queries
+}
+
+func (e *ExecutionTree) checkResultsForRun(run *ControlRun) []*introspection.CheckResult {
+	newResult := func() *introspection.CheckResult {
+		r := &introspection.CheckResult{
+			ControlName:  run.FullName,
+			ControlTitle: run.Title,
+			Severity:     run.Severity,
+		}
+		if run.Group != nil {
+			r.BenchmarkName = run.Group.GroupId
+		}
+		return r
+	}
+
+	// a control which failed to run has no rows - store its error so it is included in the history
+	if run.GetError() != nil {
+		r := newResult()
+		r.Status = constants.ControlError
+		r.Reason = run.GetError().Error()
+		return []*introspection.CheckResult{r}
+	}
+
+	res := make([]*introspection.CheckResult, len(run.Rows))
+	for i, row := range run.Rows {
+		r := newResult()
+		r.Resource = row.Resource
+		r.Status = row.Status
+		r.Reason = row.Reason
+		r.Dimensions = make(map[string]string, len(row.Dimensions))
+		for _, d := range row.Dimensions {
+			r.Dimensions[d.Key] = d.Value
+		}
+		res[i] = r
+	}
+	return res
+}
@@ -0,0 +1,84 @@
+package controlexecute
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/introspection"
+)
+
+func TestStoreResultsQueries(t *testing.T) {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	group := &ResultGroup{GroupId: "mod.benchmark.b1"}
+	tree := &ExecutionTree{
+		Root:      &ResultGroup{Summary: &GroupSummary{}},
+		StartTime: startTime,
+		EndTime:   startTime.Add(time.Minute),
+		ControlRuns: []*ControlRun{
+			{
+				FullName: "mod.control.c1",
+				Severity: "high",
+				Group:    group,
+				Rows: ResultRows{
+					{Resource: "a", Status: constants.ControlAlarm, Reason: "a is public", Dimensions: []Dimension{{Key: "region", Value: "us-east-1"}}},
+					{Resource: "b", Status: constants.ControlOk, Reason: "b is private"},
+				},
+			},
+			{
+				FullName:       "mod.control.c2",
+				Group:          group,
+				runError:       errors.New("table not found"),
+				RunErrorString: "table not found",
+			},
+		},
+	}
+
+	queries := tree.storeResultsQueries("run1", "benchmark.b1", "mod", 0)
+	var inserts [][]any
+	for _, q := range queries {
+		if strings.HasPrefix(q.Query, "DELETE") {
+			t.Errorf("expected no history to be deleted when there is no retention period")
+		}
+		if strings.Contains(q.Query, "INSERT INTO "+constants.InternalSchema+"."+constants.CheckResultTable) {
+			inserts = append(inserts, q.Args)
+		}
+	}
+	// the tables must be created before the run is stored
+	if !strings.HasPrefix(queries[0].Query, "CREATE TABLE IF NOT EXISTS") {
+		t.Errorf("expected the history tables to be created before storing results")
+	}
+
+	// one row per result, and a single error row for the failed control
+	if len(inserts) != 3 {
+		t.Fatalf("expected 3 result rows, got %d", len(inserts))
+	}
+	// args are: run_id, start_time, control_name, control_title, benchmark_name, severity, resource, status, reason, dimensions
+	if inserts[0][2] != "mod.control.c1" || inserts[0][4] != "mod.benchmark.b1" || inserts[0][5] != "high" || inserts[0][6] != "a" || inserts[0][7] != constants.ControlAlarm {
+		t.Errorf("unexpected result row: %v", inserts[0])
+	}
+	if dimensions := inserts[0][9].(map[string]string); dimensions["region"] != "us-east-1" {
+		t.Errorf("expected dimensions to be stored, got %v", dimensions)
+	}
+	if inserts[2][2] != "mod.control.c2" || inserts[2][7] != constants.ControlError || inserts[2][8] != "table not found" {
+		t.Errorf("unexpected error row: %v", inserts[2])
+	}
+
+	// with a retention period, runs started before the retention period are deleted
+	queries = tree.storeResultsQueries("run1", "benchmark.b1", "mod", 24*time.Hour)
+	expectedDelete := introspection.GetCheckRunDeleteSql(startTime.Add(-24 * time.Hour))
+	found := false
+	for _, q := range queries {
+		if q.Query == expectedDelete.Query {
+			found = true
+			if q.Args[0] != expectedDelete.Args[0] {
+				t.Errorf("expected runs before %v to be deleted, got %v", expectedDelete.Args[0], q.Args[0])
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected history older than the retention period to be deleted")
+	}
+}
@@ -51,6 +51,9 @@ type ResultRow struct {
 	Status string `json:"status" csv:"status"`
 	// dimensions for this row
 	Dimensions []Dimension `json:"dimensions"`
+	// if the row is suppressed, the name and justification of the matching control exception
+	Exception     string `json:"exception,omitempty"`
+	Justification string `json:"justification,omitempty"`
 	// parent control run
 	Run *ControlRun `json:"-"`
 	// source control
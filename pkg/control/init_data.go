@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/control/controldisplay"
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/initialisation"
 	"github.com/turbot/steampipe/pkg/statushooks"
@@ -19,6 +20,9 @@ type InitData struct {
 	initialisation.InitData
 	OutputFormatter          controldisplay.Formatter
 	ControlFilterWhereClause string
+	// if a baseline was specified, the results of the baseline and the formatter used to display the differences
+	Baseline      *controlexecute.Baseline
+	DiffFormatter controldisplay.DiffFormatter
 }
 
 // NewInitData returns a new InitData object
@@ -82,6 +86,13 @@ func NewInitData(ctx context.Context) *InitData {
 	}
 	i.OutputFormatter = formatter
 
+	if baselinePath := viper.GetString(constants.ArgBaseline); baselinePath != "" {
+		if err := i.loadBaseline(baselinePath, output); err != nil {
+			i.Result.Error = err
+			return i
+		}
+	}
+
 	i.setControlFilterClause()
 
 	// initialize
@@ -137,6 +148,21 @@ func generateWhereClauseFromTags(tags []string) string {
 	return strings.Join(whereComponents, " AND ")
 }
 
+// loadBaseline loads the baseline results and resolves the formatter used to display the differences
+func (i *InitData) loadBaseline(baselinePath, output string) error {
+	diffFormatter, err := controldisplay.GetDiffFormatter(output)
+	if err != nil {
+		return err
+	}
+	baseline, err := controlexecute.LoadBaseline(baselinePath)
+	if err != nil {
+		return err
+	}
+	i.Baseline = baseline
+	i.DiffFormatter = diffFormatter
+	return nil
+}
+
 // register exporters for each of the supported check formats
 func (i *InitData) registerCheckExporters() {
 	exporters, err := controldisplay.GetExporters()
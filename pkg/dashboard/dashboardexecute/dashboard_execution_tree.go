@@ -7,7 +7,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/spf13/viper"
 	"github.com/turbot/steampipe/pkg/connection_sync"
+	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardevents"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
 	"github.com/turbot/steampipe/pkg/db/db_common"
@@ -34,6 +36,13 @@ type DashboardExecutionTree struct {
 	inputLock   sync.Mutex
 	inputValues map[string]any
 	id          string
+	// the time query results are shared with other sessions - if zero, results are not cached
+	leafDataCacheTtl time.Duration
+	// the time the tree was created - refreshes are scheduled from this
+	createTime time.Time
+	// a refresh re-executes a completed execution in the background - it does not publish events as it runs,
+	// and does not read query results from the cache
+	isRefresh bool
 }
 
 func NewDashboardExecutionTree(rootName string, sessionId string, client db_common.Client, workspace *workspace.Workspace) (*DashboardExecutionTree, error) {
@@ -46,6 +55,9 @@ func NewDashboardExecutionTree(rootName string, sessionId string, client db_comm
 		workspace:     workspace,
 		runComplete:   make(chan dashboardtypes.DashboardTreeRun, 1),
 		inputValues:   make(map[string]any),
+		// read the cache ttl once, to avoid concurrent access to viper by the leaf runs
+		leafDataCacheTtl: time.Duration(viper.GetInt(constants.ArgDashboardCacheTtl)) * time.Second,
+		createTime:       time.Now(),
 	}
 	executionTree.id = fmt.Sprintf("%p", executionTree)
 
@@ -128,20 +140,23 @@ func (e *DashboardExecutionTree) Execute(ctx context.Context) {
 	// store context
 	cancelCtx, cancel := context.WithCancel(ctx)
 	e.cancel = cancel
-	workspace := e.workspace
 
 	// perform any necessary initialisation
 	// (e.g. check run creates the control execution tree)
+	// if this fails, the execution has not started, so send an ExecutionError event rather than ExecutionComplete
 	e.Root.Initialise(cancelCtx)
-	if e.Root.GetError() != nil {
+	if err := e.Root.GetError(); err != nil {
+		e.publishExecutionError(ctx, err)
 		return
 	}
 
 	// TODO should we always wait even with non custom search path?
 	// if there is a custom search path, wait until the first connection of each plugin has loaded
+	// (use the cancel context, so cancelling the execution stops the wait)
 	if customSearchPath := e.client.GetCustomSearchPath(); customSearchPath != nil {
-		if err := connection_sync.WaitForSearchPathSchemas(ctx, e.client, customSearchPath); err != nil {
+		if err := connection_sync.WaitForSearchPathSchemas(cancelCtx, e.client, customSearchPath); err != nil {
 			e.Root.SetError(ctx, err)
+			e.publishExecutionError(ctx, err)
 			return
 		}
 	}
@@ -153,9 +168,10 @@ func (e *DashboardExecutionTree) Execute(ctx context.Context) {
 	immutablePanels, err := utils.JsonCloneToMap(panels)
 	if err != nil {
 		e.SetError(ctx, err)
+		e.publishExecutionError(ctx, err)
 		return
 	}
-	workspace.PublishDashboardEvent(ctx, &dashboardevents.ExecutionStarted{
+	e.publishEvent(ctx, &dashboardevents.ExecutionStarted{
 		Root:        e.Root,
 		Session:     e.sessionId,
 		ExecutionId: e.id,
@@ -166,7 +182,7 @@ func (e *DashboardExecutionTree) Execute(ctx context.Context) {
 	})
 	defer func() {
 
-		e := &dashboardevents.ExecutionComplete{
+		event := &dashboardevents.ExecutionComplete{
 			Root:        e.Root,
 			Session:     e.sessionId,
 			ExecutionId: e.id,
@@ -179,7 +195,7 @@ func (e *DashboardExecutionTree) Execute(ctx context.Context) {
 			StartTime:  startTime,
 			EndTime:    time.Now(),
 		}
-		workspace.PublishDashboardEvent(ctx, e)
+		e.publishEvent(ctx, event)
 	}()
 
 	log.Println("[TRACE]", "begin DashboardExecutionTree.Execute")
@@ -195,6 +211,24 @@ func (e *DashboardExecutionTree) Execute(ctx context.Context) {
 	e.Root.Execute(cancelCtx)
 }
 
+// publishEvent publishes the event to the workspace
+// (unless this is a refresh, whose changes are published when it completes)
+func (e *DashboardExecutionTree) publishEvent(ctx context.Context, event dashboardevents.DashboardEvent) {
+	if e.isRefresh {
+		return
+	}
+	e.workspace.PublishDashboardEvent(ctx, event)
+}
+
+// publishExecutionError publishes an ExecutionError event for an execution which failed before it started
+func (e *DashboardExecutionTree) publishExecutionError(ctx context.Context, err error) {
+	e.publishEvent(ctx, &dashboardevents.ExecutionError{
+		Error:     err,
+		Session:   e.sessionId,
+		Timestamp: time.Now(),
+	})
+}
+
 // GetRunStatus returns the stats of the Root run
 func (e *DashboardExecutionTree) GetRunStatus() dashboardtypes.RunStatus {
 	return e.Root.GetRunStatus()
@@ -242,6 +276,14 @@ func (e *DashboardExecutionTree) SetInputValues(inputValues map[string]any) {
 	}
 }
 
+// getInputValues returns a copy of the input values
+func (e *DashboardExecutionTree) getInputValues() map[string]any {
+	e.inputLock.Lock()
+	defer e.inputLock.Unlock()
+
+	return maps.Clone(e.inputValues)
+}
+
 // ChildCompleteChan implements DashboardParent
 func (e *DashboardExecutionTree) ChildCompleteChan() chan dashboardtypes.DashboardTreeRun {
 	return e.runComplete
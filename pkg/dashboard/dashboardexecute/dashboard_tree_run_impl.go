@@ -161,7 +161,7 @@ func (r *DashboardTreeRunImpl) setStatus(ctx context.Context, status dashboardty
 	// TODO [node_reuse] do this a different way https://github.com/turbot/steampipe/issues/2919
 	// TACTICAL: pass the full run struct - 'r.run', rather than ourselves - so we serialize all properties
 	e, _ := dashboardevents.NewLeafNodeUpdate(r.run, r.executionTree.sessionId, r.executionTree.id)
-	r.executionTree.workspace.PublishDashboardEvent(ctx, e)
+	r.executionTree.publishEvent(ctx, e)
 
 }
 
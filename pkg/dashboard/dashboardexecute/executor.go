@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
 	"sync"
 	"time"
 
@@ -13,7 +12,6 @@ import (
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardevents"
 	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
 	"github.com/turbot/steampipe/pkg/db/db_common"
-	"github.com/turbot/steampipe/pkg/utils"
 	"github.com/turbot/steampipe/pkg/workspace"
 )
 
@@ -21,6 +19,8 @@ type DashboardExecutor struct {
 	// map of executions, keyed by session id
 	executions    map[string]*DashboardExecutionTree
 	executionLock sync.Mutex
+	// map of refreshes in progress, keyed by session id
+	refreshes map[string]*DashboardExecutionTree
 	// is this an interactive execution
 	// i.e. inputs may be specified _after_ execution starts
 	// false when running a single dashboard in batch mode
@@ -30,6 +30,7 @@ type DashboardExecutor struct {
 func newDashboardExecutor() *DashboardExecutor {
 	return &DashboardExecutor{
 		executions: make(map[string]*DashboardExecutionTree),
+		refreshes:  make(map[string]*DashboardExecutionTree),
 		// default to interactive execution
 		interactive: true,
 	}
@@ -37,7 +38,19 @@ func newDashboardExecutor() *DashboardExecutor {
 
 var Executor = newDashboardExecutor()
 
-func (e *DashboardExecutor) ExecuteDashboard(ctx context.Context, sessionId, dashboardName string, inputs map[string]any, workspace *workspace.Workspace, client db_common.Client) (err error) {
+func (e *DashboardExecutor) ExecuteDashboard(ctx context.Context, sessionId, dashboardName string, inputs map[string]any, workspace *workspace.Workspace, client db_common.Client) error {
+	// if inputs must be provided before execution (i.e. this is a batch dashboard execution),
+	// verify all required inputs are provided
+	return e.executeDashboard(ctx, sessionId, dashboardName, inputs, workspace, client, !e.interactive)
+}
+
+// ExecuteDashboardWithInputs executes the dashboard, first verifying all required inputs are provided
+// this is used for executions which cannot provide inputs after execution starts, e.g. API requests
+func (e *DashboardExecutor) ExecuteDashboardWithInputs(ctx context.Context, sessionId, dashboardName string, inputs map[string]any, workspace *workspace.Workspace, client db_common.Client) error {
+	return e.executeDashboard(ctx, sessionId, dashboardName, inputs, workspace, client, true)
+}
+
+func (e *DashboardExecutor) executeDashboard(ctx context.Context, sessionId, dashboardName string, inputs map[string]any, workspace *workspace.Workspace, client db_common.Client, validateInputs bool) (err error) {
 	var executionTree *DashboardExecutionTree
 	defer func() {
 		if err != nil && ctx.Err() != nil {
@@ -63,10 +76,10 @@ func (e *DashboardExecutor) ExecuteDashboard(ctx context.Context, sessionId, das
 		return err
 	}
 
-	// if inputs must be provided before execution (i.e. this is a batch dashboard execution),
-	// verify all required inputs are provided
-	if err = e.validateInputs(executionTree, inputs); err != nil {
-		return err
+	if validateInputs {
+		if err = e.validateInputs(executionTree, inputs); err != nil {
+			return err
+		}
 	}
 
 	// add to execution map
@@ -82,21 +95,16 @@ func (e *DashboardExecutor) ExecuteDashboard(ctx context.Context, sessionId, das
 	return nil
 }
 
-// if inputs must be provided before execution (i.e. this is a batch dashboard execution),
 // verify all required inputs are provided
 func (e *DashboardExecutor) validateInputs(executionTree *DashboardExecutionTree, inputs map[string]any) error {
-	if e.interactive {
-		// interactive dashboard execution - no need to validate
-		return nil
-	}
 	var missingInputs []string
 	for _, inputName := range executionTree.InputRuntimeDependencies() {
 		if _, ok := inputs[inputName]; !ok {
 			missingInputs = append(missingInputs, inputName)
 		}
 	}
-	if missingCount := len(missingInputs); missingCount > 0 {
-		return fmt.Errorf("%s '%s' must be provided using '--dashboard-input name=value'", utils.Pluralize("input", missingCount), strings.Join(missingInputs, ","))
+	if len(missingInputs) > 0 {
+		return &MissingInputsError{Inputs: missingInputs}
 	}
 
 	return nil
@@ -186,6 +194,11 @@ func (e *DashboardExecutor) clearDependentInputs(root dashboardtypes.DashboardTr
 }
 
 func (e *DashboardExecutor) CancelExecutionForSession(_ context.Context, sessionId string) {
+	// cancel any refresh in progress - it would replace the execution when it completes
+	if refreshTree, found := e.getRefresh(sessionId); found {
+		refreshTree.Cancel()
+	}
+
 	// find the execution
 	executionTree, found := e.getExecution(sessionId)
 	if !found {
@@ -221,3 +234,42 @@ func (e *DashboardExecutor) removeExecution(sessionId string) {
 
 	delete(e.executions, sessionId)
 }
+
+// replaceExecution replaces the execution for the session, only if it is still the expected execution
+func (e *DashboardExecutor) replaceExecution(sessionId string, expected, executionTree *DashboardExecutionTree) bool {
+	e.executionLock.Lock()
+	defer e.executionLock.Unlock()
+
+	if e.executions[sessionId] != expected {
+		return false
+	}
+	e.executions[sessionId] = executionTree
+	return true
+}
+
+func (e *DashboardExecutor) getRefresh(sessionId string) (*DashboardExecutionTree, bool) {
+	e.executionLock.Lock()
+	defer e.executionLock.Unlock()
+
+	refreshTree, found := e.refreshes[sessionId]
+	return refreshTree, found
+}
+
+// addRefresh adds the refresh for the session, unless there is already a refresh in progress
+func (e *DashboardExecutor) addRefresh(sessionId string, refreshTree *DashboardExecutionTree) bool {
+	e.executionLock.Lock()
+	defer e.executionLock.Unlock()
+
+	if _, found := e.refreshes[sessionId]; found {
+		return false
+	}
+	e.refreshes[sessionId] = refreshTree
+	return true
+}
+
+func (e *DashboardExecutor) removeRefresh(sessionId string) {
+	e.executionLock.Lock()
+	defer e.executionLock.Unlock()
+
+	delete(e.refreshes, sessionId)
+}
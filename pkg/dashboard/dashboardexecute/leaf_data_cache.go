@@ -0,0 +1,128 @@
+package dashboardexecute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"golang.org/x/sync/singleflight"
+)
+
+type leafDataCacheEntry struct {
+	data         *dashboardtypes.LeafData
+	timingResult *queryresult.TimingResult
+	expires      time.Time
+}
+
+// leafDataCache is a TTL bound cache of leaf run query results, shared by all dashboard sessions
+// it is keyed by the resolved SQL, args and search path of the query
+// NOTE: cached LeafData is shared between runs, so must not be mutated
+type leafDataCache struct {
+	entries map[string]*leafDataCacheEntry
+	mut     sync.Mutex
+	// used to ensure concurrent executions of the same query (e.g. a dashboard opened by multiple users at once)
+	// result in a single database query
+	inFlight singleflight.Group
+}
+
+func newLeafDataCache() *leafDataCache {
+	return &leafDataCache{entries: make(map[string]*leafDataCacheEntry)}
+}
+
+// the cache shared by all dashboard executions in this process
+var sharedLeafDataCache = newLeafDataCache()
+
+// getOrExecute returns the cached result for the given key if there is an unexpired entry,
+// otherwise it calls execute and (if successful) caches the result for the given ttl
+// the returned bool indicates whether the result was read from the cache
+func (c *leafDataCache) getOrExecute(key string, ttl time.Duration, execute func() (*leafDataCacheEntry, error)) (*leafDataCacheEntry, bool, error) {
+	if entry, ok := c.get(key); ok {
+		return entry, true, nil
+	}
+	return c.executeAndSet(key, ttl, execute)
+}
+
+// refresh calls execute (ignoring any cached result) and caches the result for the given ttl
+// concurrent refreshes of the same key share a single execution
+func (c *leafDataCache) refresh(key string, ttl time.Duration, execute func() (*leafDataCacheEntry, error)) (*leafDataCacheEntry, error) {
+	entry, _, err := c.executeAndSet(key, ttl, execute)
+	return entry, err
+}
+
+// executeAndSet calls execute and (if successful) caches the result for the given ttl
+// the returned bool indicates whether the result was shared from a concurrent execution
+func (c *leafDataCache) executeAndSet(key string, ttl time.Duration, execute func() (*leafDataCacheEntry, error)) (*leafDataCacheEntry, bool, error) {
+	// keep track of whether it was this call which executed the query
+	// (if not, we are sharing the result of a concurrent execution)
+	executed := false
+	res, err, _ := c.inFlight.Do(key, func() (any, error) {
+		executed = true
+		entry, err := execute()
+		if err != nil {
+			return nil, err
+		}
+		c.set(key, entry, ttl)
+		return entry, nil
+	})
+	if err != nil {
+		if !executed {
+			// the execution we shared may have failed because the context of its session was cancelled
+			// - execute the query ourselves
+			entry, err := execute()
+			return entry, false, err
+		}
+		return nil, false, err
+	}
+	// if we shared the result of another execution, report it as a cache hit
+	return res.(*leafDataCacheEntry), !executed, nil
+}
+
+func (c *leafDataCache) get(key string) (*leafDataCacheEntry, bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry, true
+}
+
+func (c *leafDataCache) set(key string, entry *leafDataCacheEntry, ttl time.Duration) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	now := time.Now()
+	// remove any expired entries, so the cache does not grow unbounded
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	entry.expires = now.Add(ttl)
+	c.entries[key] = entry
+}
+
+// leafDataCacheKey builds the cache key for a query from its resolved SQL, args and the search path it runs with
+func leafDataCacheKey(sql string, args []any, searchPath []string) (string, error) {
+	argsJson, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	for _, s := range []string{sql, string(argsJson), strings.Join(searchPath, ",")} {
+		hash.Write([]byte(s))
+		// separate the components so they cannot run into each other
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
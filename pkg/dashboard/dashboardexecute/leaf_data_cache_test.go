@@ -0,0 +1,132 @@
+package dashboardexecute
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+)
+
+func TestLeafDataCacheSharesConcurrentExecutions(t *testing.T) {
+	cache := newLeafDataCache()
+	var executions atomic.Int32
+	// block the execution until all callers are waiting for it
+	release := make(chan struct{})
+	execute := func() (*leafDataCacheEntry, error) {
+		executions.Add(1)
+		<-release
+		return &leafDataCacheEntry{data: &dashboardtypes.LeafData{}}, nil
+	}
+
+	const callers = 5
+	var wg sync.WaitGroup
+	var cacheHits atomic.Int32
+	entries := make([]*leafDataCacheEntry, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			entry, cacheHit, err := cache.getOrExecute("key", time.Minute, execute)
+			if err != nil {
+				t.Error(err)
+			}
+			if cacheHit {
+				cacheHits.Add(1)
+			}
+			entries[i] = entry
+		}(i)
+	}
+	// give the callers time to join the in-flight execution
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if executions.Load() != 1 {
+		t.Errorf("expected concurrent callers to share 1 execution, got %d executions", executions.Load())
+	}
+	if cacheHits.Load() != callers-1 {
+		t.Errorf("expected %d cache hits, got %d", callers-1, cacheHits.Load())
+	}
+	for _, entry := range entries {
+		if entry != entries[0] {
+			t.Errorf("expected all callers to get the same result")
+		}
+	}
+}
+
+func TestLeafDataCacheExpiry(t *testing.T) {
+	cache := newLeafDataCache()
+	executions := 0
+	execute := func() (*leafDataCacheEntry, error) {
+		executions++
+		return &leafDataCacheEntry{data: &dashboardtypes.LeafData{}}, nil
+	}
+
+	const ttl = 50 * time.Millisecond
+	if _, cacheHit, _ := cache.getOrExecute("key", ttl, execute); cacheHit {
+		t.Errorf("expected a cache miss for the first execution")
+	}
+	if _, cacheHit, _ := cache.getOrExecute("key", ttl, execute); !cacheHit {
+		t.Errorf("expected a cache hit before the ttl expires")
+	}
+	time.Sleep(2 * ttl)
+	if _, cacheHit, _ := cache.getOrExecute("key", ttl, execute); cacheHit {
+		t.Errorf("expected a cache miss after the ttl expires")
+	}
+	if executions != 2 {
+		t.Errorf("expected 2 executions, got %d", executions)
+	}
+
+	// a refresh always executes, and updates the cached entry
+	refreshed, err := cache.refresh("key", ttl, execute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, cacheHit, _ := cache.getOrExecute("key", ttl, execute); !cacheHit || entry != refreshed {
+		t.Errorf("expected the refreshed result to be cached")
+	}
+
+	// failed executions are not cached
+	failing := func() (*leafDataCacheEntry, error) {
+		executions++
+		return nil, errors.New("query failed")
+	}
+	executions = 0
+	for i := 0; i < 2; i++ {
+		if _, _, err := cache.getOrExecute("failing", ttl, failing); err == nil {
+			t.Errorf("expected an error")
+		}
+	}
+	if executions != 2 {
+		t.Errorf("expected failed executions not to be cached, got %d executions", executions)
+	}
+}
+
+func TestLeafDataCacheKey(t *testing.T) {
+	searchPath := []string{"aws", "public"}
+	key := func(sql string, args []any, searchPath []string) string {
+		k, err := leafDataCacheKey(sql, args, searchPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	base := key("select $1", []any{"us-east-1"}, searchPath)
+	if key("select $1", []any{"us-east-1"}, searchPath) != base {
+		t.Errorf("expected the same query, args and search path to have the same key")
+	}
+	// queries with different input values must not share results
+	if key("select $1", []any{"us-west-2"}, searchPath) == base {
+		t.Errorf("expected different args to have different keys")
+	}
+	if key("select $1", []any{"us-east-1"}, []string{"azure", "public"}) == base {
+		t.Errorf("expected different search paths to have different keys")
+	}
+	if key("select $2", []any{"us-east-1"}, searchPath) == base {
+		t.Errorf("expected different sql to have different keys")
+	}
+}
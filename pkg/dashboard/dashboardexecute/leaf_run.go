@@ -19,6 +19,9 @@ type LeafRun struct {
 
 	Data         *dashboardtypes.LeafData  `json:"data,omitempty"`
 	TimingResult *queryresult.TimingResult `json:"-"`
+	// whether the data was read from the cache shared between dashboard sessions
+	// (nil if the run has no query or caching is disabled)
+	CacheHit *bool `json:"cache_hit,omitempty"`
 	// function called when the run is complete
 	// this property populated for 'with' runs
 	onComplete func()
@@ -178,17 +181,56 @@ func (*LeafRun) IsSnapshotPanel() {}
 func (r *LeafRun) executeQuery(ctx context.Context) error {
 	log.Printf("[TRACE] LeafRun '%s' SQL resolved, executing", r.resource.Name())
 
+	ttl := r.executionTree.leafDataCacheTtl
+	if ttl <= 0 {
+		entry, err := r.doExecuteQuery(ctx)
+		if err != nil {
+			return err
+		}
+		r.Data = entry.data
+		r.TimingResult = entry.timingResult
+		return nil
+	}
+
+	cacheKey, err := leafDataCacheKey(r.executeSQL, r.Args, r.executionTree.client.GetRequiredSessionSearchPath())
+	if err != nil {
+		return err
+	}
+	execute := func() (*leafDataCacheEntry, error) {
+		return r.doExecuteQuery(ctx)
+	}
+	var entry *leafDataCacheEntry
+	var cacheHit bool
+	if r.executionTree.isRefresh {
+		// a refresh must not read stale results from the cache - but update the cache for other sessions
+		entry, err = sharedLeafDataCache.refresh(cacheKey, ttl, execute)
+	} else {
+		entry, cacheHit, err = sharedLeafDataCache.getOrExecute(cacheKey, ttl, execute)
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("[TRACE] LeafRun '%s' cache hit: %v", r.resource.Name(), cacheHit)
+
+	r.Data = entry.data
+	r.TimingResult = entry.timingResult
+	r.CacheHit = &cacheHit
+	return nil
+}
+
+func (r *LeafRun) doExecuteQuery(ctx context.Context) (*leafDataCacheEntry, error) {
 	queryResult, err := r.executionTree.client.ExecuteSync(ctx, r.executeSQL, r.Args...)
 	if err != nil {
 		log.Printf("[TRACE] LeafRun '%s' query failed: %s", r.resource.Name(), err.Error())
-		return err
+		return nil, err
 
 	}
 	log.Printf("[TRACE] LeafRun '%s' complete", r.resource.Name())
 
-	r.Data = dashboardtypes.NewLeafData(queryResult)
-	r.TimingResult = queryResult.TimingResult
-	return nil
+	return &leafDataCacheEntry{
+		data:         dashboardtypes.NewLeafData(queryResult),
+		timingResult: queryResult.TimingResult,
+	}, nil
 }
 
 func (r *LeafRun) combineChildData() {
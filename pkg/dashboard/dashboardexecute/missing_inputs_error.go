@@ -0,0 +1,18 @@
+package dashboardexecute
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// MissingInputsError is returned when a dashboard is executed without values for all of its inputs
+// and the inputs cannot be provided after execution starts
+type MissingInputsError struct {
+	Inputs []string
+}
+
+func (e *MissingInputsError) Error() string {
+	return fmt.Sprintf("%s '%s' must be provided using '--dashboard-input name=value'", utils.Pluralize("input", len(e.Inputs)), strings.Join(e.Inputs, ","))
+}
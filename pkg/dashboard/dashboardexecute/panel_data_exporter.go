@@ -0,0 +1,97 @@
+package dashboardexecute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/export"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"golang.org/x/exp/slices"
+)
+
+// the panel types whose data is exported by the PanelDataExporter
+var exportedPanelTypes = []string{modconfig.BlockTypeTable, modconfig.BlockTypeChart, modconfig.BlockTypeCard}
+
+// PanelDataExporter exports the data of each table, chart and card panel of a dashboard snapshot to a separate file,
+// named after the panel
+// the files are written using the query result exporter for the format
+type PanelDataExporter struct {
+	export.ExporterBase
+	resultExporter export.Exporter
+}
+
+// PanelDataExporters returns a panel data exporter for each of the supported panel data export formats
+func PanelDataExporters() []export.Exporter {
+	var res []export.Exporter
+	for _, e := range display.QueryResultExporters() {
+		if e.Name() == constants.OutputFormatCSV || e.Name() == constants.OutputFormatJSON {
+			res = append(res, &PanelDataExporter{resultExporter: e})
+		}
+	}
+	return res
+}
+
+// Export implements Exporter
+func (e *PanelDataExporter) Export(ctx context.Context, input export.ExportSourceData, dirPath string) error {
+	_, err := e.ExportFiles(ctx, input, dirPath)
+	return err
+}
+
+// ExportFiles implements MultiFileExporter
+func (e *PanelDataExporter) ExportFiles(ctx context.Context, input export.ExportSourceData, dirPath string) ([]string, error) {
+	snapshot, ok := input.(*dashboardtypes.SteampipeSnapshot)
+	if !ok {
+		return nil, fmt.Errorf("PanelDataExporter input must be *dashboardtypes.SteampipeSnapshot")
+	}
+
+	// sort the panels so the files are reported in a consistent order
+	var panelNames []string
+	for name, panel := range snapshot.Panels {
+		if leafRun, ok := panel.(*LeafRun); ok && leafRun.Data != nil && slices.Contains(exportedPanelTypes, leafRun.NodeType) {
+			panelNames = append(panelNames, name)
+		}
+	}
+	if len(panelNames) == 0 {
+		return nil, nil
+	}
+	sort.Strings(panelNames)
+
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range panelNames {
+		fileName := name + e.FileExtension()
+		data := snapshot.Panels[name].(*LeafRun).Data
+		if err := e.resultExporter.Export(ctx, leafDataAsResult(data), filepath.Join(dirPath, fileName)); err != nil {
+			return files, fmt.Errorf("failed to export data for %s: %s", name, err.Error())
+		}
+		files = append(files, fileName)
+	}
+	return files, nil
+}
+
+func (e *PanelDataExporter) FileExtension() string {
+	return e.resultExporter.FileExtension()
+}
+
+func (e *PanelDataExporter) Name() string {
+	return e.resultExporter.Name()
+}
+
+// leafDataAsResult returns a query result which streams the rows of the leaf data
+func leafDataAsResult(data *dashboardtypes.LeafData) *queryresult.Result {
+	buffered := &queryresult.BufferedResult{Cols: data.Columns}
+	// use the row values in column order, as rows keyed by column name lose the values of duplicate column names
+	for _, values := range data.RowValues() {
+		buffered.Rows = append(buffered.Rows, &queryresult.RowResult{Data: values})
+	}
+	return buffered.Replay()
+}
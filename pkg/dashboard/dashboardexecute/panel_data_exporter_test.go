@@ -0,0 +1,60 @@
+package dashboardexecute
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func TestPanelDataExporterDuplicateColumnNames(t *testing.T) {
+	// the columns of a query result may have the same name, e.g. 'select a.name, b.name from a join b'
+	data := dashboardtypes.NewLeafData(&queryresult.SyncQueryResult{
+		Cols: []*queryresult.ColumnDef{
+			{Name: "name", DataType: "TEXT"},
+			{Name: "name", DataType: "TEXT"},
+			{Name: "count", DataType: "INT8"},
+		},
+		Rows: []interface{}{
+			&queryresult.RowResult{Data: []interface{}{"a", "b", int64(1)}},
+			&queryresult.RowResult{Data: []interface{}{"c", "d", int64(2)}},
+		},
+	})
+	leafRun := &LeafRun{Data: data}
+	leafRun.NodeType = modconfig.BlockTypeTable
+	snapshot := &dashboardtypes.SteampipeSnapshot{
+		Panels: map[string]dashboardtypes.SnapshotPanel{"mod.table.t1": leafRun},
+	}
+
+	var exporter *PanelDataExporter
+	for _, e := range PanelDataExporters() {
+		if e.Name() == constants.OutputFormatCSV {
+			exporter = e.(*PanelDataExporter)
+		}
+	}
+	if exporter == nil {
+		t.Fatal("no csv panel data exporter")
+	}
+
+	dirPath := t.TempDir()
+	files, err := exporter.ExportFiles(context.Background(), snapshot, dirPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != "mod.table.t1.csv" {
+		t.Fatalf("ExportFiles() files = %v, expected [mod.table.t1.csv]", files)
+	}
+	got, err := os.ReadFile(filepath.Join(dirPath, files[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "name,name,count\na,b,1\nc,d,2\n"
+	if string(got) != expected {
+		t.Errorf("exported data got:\n%s\nexpected:\n%s", got, expected)
+	}
+}
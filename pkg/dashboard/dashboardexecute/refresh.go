@@ -0,0 +1,93 @@
+package dashboardexecute
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardevents"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+)
+
+// RefreshDashboard re-executes the dashboard for the session, if the current execution is complete and
+// was started at least interval ago
+// the refresh is executed in the background, then replaces the current execution and publishes
+// LeafNodeUpdated events for the panels which have changed - so the UI updates in place rather than resetting
+func (e *DashboardExecutor) RefreshDashboard(ctx context.Context, sessionId string, interval time.Duration) {
+	executionTree, found := e.getExecution(sessionId)
+	if !found || !executionTree.GetRunStatus().IsFinished() || time.Since(executionTree.createTime) < interval {
+		return
+	}
+	// only dashboards are refreshed - benchmark results are not published as leaf node updates
+	if _, ok := executionTree.Root.(*DashboardRun); !ok {
+		return
+	}
+
+	refreshTree, err := NewDashboardExecutionTree(executionTree.dashboardName, sessionId, executionTree.client, executionTree.workspace)
+	if err != nil {
+		log.Printf("[WARN] failed to refresh dashboard %s: %s", executionTree.dashboardName, err.Error())
+		return
+	}
+	refreshTree.isRefresh = true
+	// the UI ignores events for any execution other than the one it is displaying,
+	// so the refresh must use the id of the execution it replaces
+	refreshTree.id = executionTree.id
+
+	if !e.addRefresh(sessionId, refreshTree) {
+		// there is already a refresh in progress
+		return
+	}
+	defer e.removeRefresh(sessionId)
+
+	log.Printf("[TRACE] refreshing dashboard %s for session %s", executionTree.dashboardName, sessionId)
+	if inputValues := executionTree.getInputValues(); len(inputValues) > 0 {
+		refreshTree.SetInputValues(inputValues)
+	}
+	refreshTree.Execute(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	// if the session has moved on (i.e. started another execution or cleared the dashboard), discard the refresh
+	if !e.replaceExecution(sessionId, executionTree, refreshTree) {
+		log.Printf("[TRACE] execution for session %s changed during refresh - discarding refresh", sessionId)
+		return
+	}
+
+	for name, run := range refreshTree.runs {
+		leafRun, ok := run.(*LeafRun)
+		// only publish runs which have completed - if the refresh failed to start, the runs will not have executed
+		if !ok || !leafRun.Status.IsFinished() || !leafRunChanged(executionTree.runs[name], leafRun) {
+			continue
+		}
+		event, err := dashboardevents.NewLeafNodeUpdate(leafRun, sessionId, refreshTree.id)
+		if err != nil {
+			log.Printf("[WARN] failed to build update for %s: %s", name, err.Error())
+			continue
+		}
+		refreshTree.workspace.PublishDashboardEvent(ctx, event)
+	}
+}
+
+// leafRunChanged returns whether the status, error or data of the refreshed run
+// differs from the previous run of the same panel
+func leafRunChanged(previous dashboardtypes.DashboardTreeRun, refreshed *LeafRun) bool {
+	previousLeafRun, ok := previous.(*LeafRun)
+	if !ok {
+		return true
+	}
+	if previousLeafRun.Status != refreshed.Status || previousLeafRun.ErrorString != refreshed.ErrorString {
+		return true
+	}
+	// compare the serialised data, as this is what is sent to the UI
+	previousData, err := json.Marshal(previousLeafRun.Data)
+	if err != nil {
+		return true
+	}
+	refreshedData, err := json.Marshal(refreshed.Data)
+	if err != nil {
+		return true
+	}
+	return string(previousData) != string(refreshedData)
+}
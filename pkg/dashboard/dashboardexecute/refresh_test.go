@@ -0,0 +1,212 @@
+package dashboardexecute
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardevents"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"github.com/turbot/steampipe/pkg/workspace"
+)
+
+const refreshTestModSource = `
+mod "test" {
+  title = "test"
+}
+
+dashboard "refresh" {
+  table "changing" {
+    sql = "select 'changing'"
+  }
+  table "static" {
+    sql = "select 'static'"
+  }
+  text "heading" {
+    value = "hello"
+  }
+}
+`
+
+// refreshTestClient returns a new value for the 'changing' query every time it is executed
+type refreshTestClient struct {
+	db_common.Client
+	executions map[string]int
+	mut        sync.Mutex
+}
+
+func (c *refreshTestClient) GetRequiredSessionSearchPath() []string {
+	return nil
+}
+
+func (c *refreshTestClient) GetCustomSearchPath() []string {
+	return nil
+}
+
+func (c *refreshTestClient) ExecuteSync(_ context.Context, sql string, _ ...any) (*queryresult.SyncQueryResult, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.executions[sql]++
+
+	var value any = "static"
+	if strings.Contains(sql, "changing") {
+		value = int64(c.executions[sql])
+	}
+	return &queryresult.SyncQueryResult{
+		Cols: []*queryresult.ColumnDef{{Name: "value", DataType: "TEXT"}},
+		Rows: []interface{}{&queryresult.RowResult{Data: []any{value}}},
+	}, nil
+}
+
+func (c *refreshTestClient) executionCount(sql string) int {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.executions[sql]
+}
+
+// refreshTestEvents records the dashboard events published by the workspace
+type refreshTestEvents struct {
+	events []dashboardevents.DashboardEvent
+	mut    sync.Mutex
+}
+
+func (e *refreshTestEvents) handle(_ context.Context, event dashboardevents.DashboardEvent) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	e.events = append(e.events, event)
+}
+
+// waitFor waits until an event matching f has been handled, then returns and clears the events handled so far
+func (e *refreshTestEvents) waitFor(t *testing.T, f func(dashboardevents.DashboardEvent) bool) []dashboardevents.DashboardEvent {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		e.mut.Lock()
+		for i, event := range e.events {
+			if f(event) {
+				events := e.events[:i+1]
+				e.events = e.events[i+1:]
+				e.mut.Unlock()
+				return events
+			}
+		}
+		e.mut.Unlock()
+	}
+	t.Fatalf("timed out waiting for dashboard event")
+	return nil
+}
+
+// publishedAfterRefresh returns the events published by the refresh
+// (a marker event is published after the refresh, as events are handled asynchronously, in order)
+func (e *refreshTestEvents) publishedAfterRefresh(t *testing.T, ctx context.Context, w *workspace.Workspace) []dashboardevents.DashboardEvent {
+	marker := &dashboardevents.WorkspaceError{}
+	w.PublishDashboardEvent(ctx, marker)
+	events := e.waitFor(t, func(event dashboardevents.DashboardEvent) bool { return event == marker })
+	return events[:len(events)-1]
+}
+
+func TestRefreshDashboard(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	modDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(modDir, "mod.sp"), []byte(refreshTestModSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set(constants.ConfigKeyBypassHomeDirModfileWarning, true)
+	defer viper.Reset()
+	w, errAndWarnings := workspace.Load(ctx, modDir)
+	if errAndWarnings.GetError() != nil {
+		t.Fatal(errAndWarnings.GetError())
+	}
+	events := &refreshTestEvents{}
+	w.RegisterDashboardEventHandler(ctx, events.handle)
+
+	client := &refreshTestClient{executions: make(map[string]int)}
+	executor := newDashboardExecutor()
+	const sessionId = "session"
+	if err := executor.ExecuteDashboard(ctx, sessionId, "test.dashboard.refresh", nil, w, client); err != nil {
+		t.Fatal(err)
+	}
+	events.waitFor(t, func(event dashboardevents.DashboardEvent) bool {
+		_, ok := event.(*dashboardevents.ExecutionComplete)
+		return ok
+	})
+	executionTree, _ := executor.getExecution(sessionId)
+
+	// the dashboard is not refreshed until the interval has passed
+	executor.RefreshDashboard(ctx, sessionId, time.Hour)
+	if refreshed := events.publishedAfterRefresh(t, ctx, w); len(refreshed) != 0 {
+		t.Errorf("expected no events before the refresh interval, got %d", len(refreshed))
+	}
+	if count := client.executionCount("select 'changing'"); count != 1 {
+		t.Errorf("expected the dashboard not to be re-run before the refresh interval, got %d executions", count)
+	}
+
+	// once the interval has passed, the dashboard is re-run
+	executor.RefreshDashboard(ctx, sessionId, 0)
+	refreshed := events.publishedAfterRefresh(t, ctx, w)
+	for _, sql := range []string{"select 'changing'", "select 'static'"} {
+		if count := client.executionCount(sql); count != 2 {
+			t.Errorf("expected '%s' to be re-run, got %d executions", sql, count)
+		}
+	}
+	// only the panel whose data changed is sent - there are no execution started or complete events,
+	// so the UI updates in place
+	if len(refreshed) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(refreshed))
+	}
+	leafNodeUpdated, ok := refreshed[0].(*dashboardevents.LeafNodeUpdated)
+	if !ok {
+		t.Fatalf("expected a LeafNodeUpdated event, got %T", refreshed[0])
+	}
+	if name := leafNodeUpdated.LeafNode["name"]; name != "test.table.changing" {
+		t.Errorf("expected an update for test.table.changing, got %v", name)
+	}
+	// the refresh uses the id of the execution it replaces, as the UI ignores events for other executions
+	if leafNodeUpdated.ExecutionId != executionTree.id || leafNodeUpdated.Session != sessionId {
+		t.Errorf("expected the update to be for the original execution")
+	}
+	if refreshTree, _ := executor.getExecution(sessionId); refreshTree == executionTree || refreshTree.id != executionTree.id {
+		t.Errorf("expected the refresh to replace the execution, keeping its id")
+	}
+}
+
+func TestLeafRunChanged(t *testing.T) {
+	newLeafRun := func(status dashboardtypes.RunStatus, errorString string, value any) *LeafRun {
+		r := &LeafRun{}
+		r.Status = status
+		r.ErrorString = errorString
+		r.Data = &dashboardtypes.LeafData{
+			Columns: []*queryresult.ColumnDef{{Name: "value"}},
+			Rows:    []map[string]any{{"value": value}},
+		}
+		return r
+	}
+	previous := newLeafRun(dashboardtypes.RunComplete, "", 1)
+
+	type leafRunChangedTest struct {
+		previous  dashboardtypes.DashboardTreeRun
+		refreshed *LeafRun
+		expected  bool
+	}
+	tests := map[string]leafRunChangedTest{
+		"unchanged":                  {previous, newLeafRun(dashboardtypes.RunComplete, "", 1), false},
+		"data changed":               {previous, newLeafRun(dashboardtypes.RunComplete, "", 2), true},
+		"status changed":             {previous, newLeafRun(dashboardtypes.RunError, "", 1), true},
+		"error changed":              {previous, newLeafRun(dashboardtypes.RunComplete, "query failed", 1), true},
+		"no previous run":            {nil, newLeafRun(dashboardtypes.RunComplete, "", 1), true},
+		"previous is not a leaf run": {&DashboardRun{}, newLeafRun(dashboardtypes.RunComplete, "", 1), true},
+	}
+	for name, test := range tests {
+		if changed := leafRunChanged(test.previous, test.refreshed); changed != test.expected {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, changed)
+		}
+	}
+}
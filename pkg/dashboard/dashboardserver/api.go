@@ -14,10 +14,9 @@ import (
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/filepaths"
-	"gopkg.in/olahol/melody.v1"
 )
 
-func startAPIAsync(ctx context.Context, webSocket *melody.Melody) chan struct{} {
+func startAPIAsync(ctx context.Context, server *Server) chan struct{} {
 	doneChan := make(chan struct{})
 
 	go func() {
@@ -26,14 +25,24 @@ func startAPIAsync(ctx context.Context, webSocket *melody.Melody) chan struct{}
 		// only add the Recovery middleware
 		router.Use(gin.Recovery())
 
+		// authentication must be applied before any route, including the static assets and the websocket upgrade
+		if len(server.authenticators) > 0 {
+			router.Use(authMiddleware(server.authenticators))
+		} else if viper.GetString(constants.ArgDashboardListen) == string(ListenTypeNetwork) {
+			OutputWarning(ctx, "Authentication is not enabled - anyone who can reach the dashboard server can run dashboards")
+		}
+
 		assetsDirectory := filepaths.EnsureDashboardAssetsDir()
 
 		router.Use(static.Serve("/", static.LocalFile(assetsDirectory, true)))
 
 		router.GET("/ws", func(c *gin.Context) {
-			webSocket.HandleRequest(c.Writer, c.Request)
+			// store the authenticated identity in the session
+			server.webSocket.HandleRequestWithKeys(c.Writer, c.Request, map[string]interface{}{identityKey: c.GetString(identityKey)})
 		})
 
+		server.registerAPIRoutes(ctx, router)
+
 		router.NoRoute(func(c *gin.Context) {
 			// https://stackoverflow.com/questions/49547/how-do-we-control-web-page-caching-across-all-browsers
 			c.Header("Cache-Control", "no-cache, no-store, must-revalidate") // HTTP 1.1.
@@ -49,19 +58,27 @@ func startAPIAsync(ctx context.Context, webSocket *melody.Melody) chan struct{}
 		}
 
 		srv := &http.Server{
-			Addr:    fmt.Sprintf("%s:%d", dashboardServerListen, dashboardServerPort),
-			Handler: router,
+			Addr:      fmt.Sprintf("%s:%d", dashboardServerListen, dashboardServerPort),
+			Handler:   router,
+			TLSConfig: server.tlsConfig,
 		}
 
 		go func() {
 			// service connections
-			if err := srv.ListenAndServe(); err != nil {
+			var err error
+			if srv.TLSConfig != nil {
+				// the certificate is provided by the TLS config
+				err = srv.ListenAndServeTLS("", "")
+			} else {
+				err = srv.ListenAndServe()
+			}
+			if err != nil {
 				log.Printf("listen: %s\n", err)
 			}
 		}()
 
 		outputReady(ctx, fmt.Sprintf("Dashboard server started on %d and listening on %s", dashboardServerPort, viper.GetString(constants.ArgDashboardListen)))
-		OutputMessage(ctx, fmt.Sprintf("Visit http://localhost:%d", dashboardServerPort))
+		OutputMessage(ctx, fmt.Sprintf("Visit %s://localhost:%d", URLScheme(), dashboardServerPort))
 		OutputMessage(ctx, "Press Ctrl+C to exit")
 		<-ctx.Done()
 		log.Println("Shutdown Server…")
@@ -0,0 +1,172 @@
+package dashboardserver
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// identityKey is the key of the authenticated identity in the gin context and the websocket session keys
+const identityKey = "identity"
+
+// Authenticator authenticates requests to the dashboard server
+type Authenticator interface {
+	// Authenticate returns the identity of the caller, and whether this authenticator accepted the request
+	Authenticate(r *http.Request) (string, bool)
+}
+
+// challenger is implemented by authenticators which can ask a browser to prompt for credentials
+type challenger interface {
+	Challenge() string
+}
+
+// newAuthenticators returns the authenticators configured in the dashboard options
+// if none are returned, requests are not authenticated
+func newAuthenticators() ([]Authenticator, error) {
+	var res []Authenticator
+	if tokens := viper.GetStringMapString(constants.ArgDashboardAuthTokens); len(tokens) > 0 {
+		res = append(res, &tokenAuthenticator{tokens: tokens})
+	}
+	if users := viper.GetStringMapString(constants.ArgDashboardAuthUsers); len(users) > 0 {
+		res = append(res, &basicAuthenticator{users: users})
+	}
+	if header := viper.GetString(constants.ArgDashboardAuthProxyHeader); header != "" {
+		proxyAuthenticator, err := newProxyHeaderAuthenticator(header, viper.GetStringSlice(constants.ArgDashboardAuthTrustedProxies))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, proxyAuthenticator)
+	}
+	return res, nil
+}
+
+// authMiddleware rejects any request which is not accepted by one of the authenticators
+// the identity of the caller is stored in the gin context
+func authMiddleware(authenticators []Authenticator) gin.HandlerFunc {
+	var challenges []string
+	for _, a := range authenticators {
+		if c, ok := a.(challenger); ok {
+			challenges = append(challenges, c.Challenge())
+		}
+	}
+	return func(c *gin.Context) {
+		for _, a := range authenticators {
+			if identity, ok := a.Authenticate(c.Request); ok {
+				c.Set(identityKey, identity)
+				c.Next()
+				return
+			}
+		}
+		for _, challenge := range challenges {
+			c.Writer.Header().Add("WWW-Authenticate", challenge)
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, apiErrorResponse{Error: "authentication required"})
+	}
+}
+
+// checkSameOrigin returns whether the Origin header of the request (if there is one) matches the request host
+// browsers send credentials automatically, so when authentication is enabled other web sites must not be able
+// to open a websocket as the logged-in user
+func checkSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		// not a browser request
+		return true
+	}
+	originUrl, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(originUrl.Host, r.Host)
+}
+
+// tokenAuthenticator accepts requests with an 'Authorization: Bearer <token>' header containing a configured token
+type tokenAuthenticator struct {
+	// map of identity to token
+	tokens map[string]string
+}
+
+func (a *tokenAuthenticator) Authenticate(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+	for identity, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return identity, true
+		}
+	}
+	return "", false
+}
+
+// basicAuthenticator accepts requests with basic auth credentials matching a configured user
+type basicAuthenticator struct {
+	// map of user name to password
+	users map[string]string
+}
+
+func (a *basicAuthenticator) Authenticate(r *http.Request) (string, bool) {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return "", false
+	}
+	expected, ok := a.users[user]
+	if !ok || subtle.ConstantTimeCompare([]byte(password), []byte(expected)) != 1 {
+		return "", false
+	}
+	return user, true
+}
+
+func (a *basicAuthenticator) Challenge() string {
+	return `Basic realm="Steampipe Dashboard", charset="UTF-8"`
+}
+
+// proxyHeaderAuthenticator accepts requests from a trusted reverse proxy which has set the identity header
+type proxyHeaderAuthenticator struct {
+	header         string
+	trustedProxies []netip.Prefix
+}
+
+func newProxyHeaderAuthenticator(header string, trustedProxies []string) (*proxyHeaderAuthenticator, error) {
+	if len(trustedProxies) == 0 {
+		return nil, fmt.Errorf("auth_trusted_proxies must be set when auth_proxy_header is set")
+	}
+	a := &proxyHeaderAuthenticator{header: header}
+	for _, p := range trustedProxies {
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			// this may be a single address
+			addr, addrErr := netip.ParseAddr(p)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy '%s': must be an IP address or CIDR range", p)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		a.trustedProxies = append(a.trustedProxies, prefix)
+	}
+	return a, nil
+}
+
+func (a *proxyHeaderAuthenticator) Authenticate(r *http.Request) (string, bool) {
+	identity := r.Header.Get(a.header)
+	if identity == "" {
+		return "", false
+	}
+	remoteAddr, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return "", false
+	}
+	for _, prefix := range a.trustedProxies {
+		if prefix.Contains(remoteAddr.Addr().Unmap()) {
+			return identity, true
+		}
+	}
+	return "", false
+}
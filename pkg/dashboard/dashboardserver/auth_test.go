@@ -0,0 +1,155 @@
+package dashboardserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"gopkg.in/olahol/melody.v1"
+)
+
+type authTest struct {
+	setup            func(r *http.Request)
+	expectedStatus   int
+	expectedIdentity string
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	proxyAuthenticator, err := newProxyHeaderAuthenticator("X-Forwarded-User", []string{"10.0.0.1", "192.168.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticators := []Authenticator{
+		&tokenAuthenticator{tokens: map[string]string{"portal": "secret-token"}},
+		&basicAuthenticator{users: map[string]string{"alice": "password"}},
+		proxyAuthenticator,
+	}
+
+	testCases := map[string]authTest{
+		"no credentials": {
+			setup:          func(r *http.Request) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"valid token": {
+			setup:            func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret-token") },
+			expectedStatus:   http.StatusOK,
+			expectedIdentity: "portal",
+		},
+		"invalid token": {
+			setup:          func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") },
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"valid basic auth": {
+			setup:            func(r *http.Request) { r.SetBasicAuth("alice", "password") },
+			expectedStatus:   http.StatusOK,
+			expectedIdentity: "alice",
+		},
+		"invalid basic auth": {
+			setup:          func(r *http.Request) { r.SetBasicAuth("alice", "wrong") },
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"proxy header from trusted address": {
+			setup: func(r *http.Request) {
+				r.RemoteAddr = "10.0.0.1:1234"
+				r.Header.Set("X-Forwarded-User", "bob")
+			},
+			expectedStatus:   http.StatusOK,
+			expectedIdentity: "bob",
+		},
+		"proxy header from trusted range": {
+			setup: func(r *http.Request) {
+				r.RemoteAddr = "192.168.1.20:1234"
+				r.Header.Set("X-Forwarded-User", "bob")
+			},
+			expectedStatus:   http.StatusOK,
+			expectedIdentity: "bob",
+		},
+		"proxy header from untrusted address": {
+			setup: func(r *http.Request) {
+				r.RemoteAddr = "10.0.0.2:1234"
+				r.Header.Set("X-Forwarded-User", "bob")
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for name, test := range testCases {
+		router := gin.New()
+		router.Use(authMiddleware(authenticators))
+		var identity string
+		router.GET("/", func(c *gin.Context) {
+			identity = c.GetString(identityKey)
+		})
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		test.setup(r)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if w.Code != test.expectedStatus {
+			t.Errorf("Test: '%s' FAILED : expected status %d, got %d", name, test.expectedStatus, w.Code)
+		}
+		if identity != test.expectedIdentity {
+			t.Errorf("Test: '%s' FAILED : expected identity '%s', got '%s'", name, test.expectedIdentity, identity)
+		}
+		if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("Test: '%s' FAILED : expected a WWW-Authenticate challenge", name)
+		}
+	}
+}
+
+func TestCheckSameOrigin(t *testing.T) {
+	testCases := map[string]struct {
+		origin   string
+		expected bool
+	}{
+		"no origin":        {"", true},
+		"same origin":      {"http://localhost:9194", true},
+		"same origin tls":  {"https://localhost:9194", true},
+		"other host":       {"https://evil.example.com", false},
+		"other port":       {"http://localhost:8080", false},
+		"invalid origin":   {"://bad", false},
+		"host is a prefix": {"http://localhost:9194.evil.example.com", false},
+	}
+
+	for name, test := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "http://localhost:9194/ws", nil)
+		if test.origin != "" {
+			r.Header.Set("Origin", test.origin)
+		}
+		if actual := checkSameOrigin(r); actual != test.expected {
+			t.Errorf("Test: '%s' FAILED : expected %v, got %v", name, test.expected, actual)
+		}
+	}
+}
+
+func TestWebsocketRejectsCrossSiteOrigin(t *testing.T) {
+	webSocket := melody.New()
+	webSocket.Upgrader.CheckOrigin = checkSameOrigin
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = webSocket.HandleRequest(w, r)
+	}))
+	defer server.Close()
+	defer webSocket.Close()
+
+	wsUrl := "ws" + strings.TrimPrefix(server.URL, "http")
+	// a page served by the dashboard server may connect
+	conn, _, err := websocket.DefaultDialer.Dial(wsUrl, http.Header{"Origin": []string{server.URL}})
+	if err != nil {
+		t.Fatalf("expected same origin websocket to connect: %v", err)
+	}
+	conn.Close()
+
+	// another web site may not
+	_, resp, err := websocket.DefaultDialer.Dial(wsUrl, http.Header{"Origin": []string{"https://evil.example.com"}})
+	if err == nil {
+		t.Fatal("expected cross-site websocket to be rejected")
+	}
+	if resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected status %d for cross-site websocket, got %v", http.StatusForbidden, resp)
+	}
+}
@@ -14,6 +14,14 @@ import (
 )
 
 func buildDashboardMetadataPayload(workspaceResources *modconfig.ResourceMaps, cloudMetadata *steampipeconfig.CloudMetadata) ([]byte, error) {
+	payload := DashboardMetadataPayload{
+		Action:   "dashboard_metadata",
+		Metadata: buildDashboardMetadata(workspaceResources, cloudMetadata),
+	}
+	return json.Marshal(payload)
+}
+
+func buildDashboardMetadata(workspaceResources *modconfig.ResourceMaps, cloudMetadata *steampipeconfig.CloudMetadata) DashboardMetadata {
 	installedMods := make(map[string]ModDashboardMetadata)
 	for _, mod := range workspaceResources.Mods {
 		// Ignore current mod
@@ -27,30 +35,27 @@ func buildDashboardMetadataPayload(workspaceResources *modconfig.ResourceMaps, c
 		}
 	}
 
-	payload := DashboardMetadataPayload{
-		Action: "dashboard_metadata",
-		Metadata: DashboardMetadata{
-			CLI: DashboardCLIMetadata{
-				Version: version.VersionString,
-			},
-			InstalledMods: installedMods,
-			Telemetry:     viper.GetString(constants.ArgTelemetry),
+	metadata := DashboardMetadata{
+		CLI: DashboardCLIMetadata{
+			Version: version.VersionString,
 		},
+		InstalledMods: installedMods,
+		Telemetry:     viper.GetString(constants.ArgTelemetry),
 	}
 
 	if mod := workspaceResources.Mod; mod != nil {
-		payload.Metadata.Mod = &ModDashboardMetadata{
+		metadata.Mod = &ModDashboardMetadata{
 			Title:     typeHelpers.SafeString(mod.Title),
 			FullName:  mod.FullName,
 			ShortName: mod.ShortName,
 		}
 	}
 	// if telemetry is enabled, send cloud metadata
-	if payload.Metadata.Telemetry != constants.TelemetryNone {
-		payload.Metadata.Cloud = cloudMetadata
+	if metadata.Telemetry != constants.TelemetryNone {
+		metadata.Cloud = cloudMetadata
 	}
 
-	return json.Marshal(payload)
+	return metadata
 }
 
 func addBenchmarkChildren(benchmark *modconfig.Benchmark, recordTrunk bool, trunk []string, trunks map[string][][]string) []ModAvailableBenchmark {
@@ -78,7 +83,10 @@ func addBenchmarkChildren(benchmark *modconfig.Benchmark, recordTrunk bool, trun
 }
 
 func buildAvailableDashboardsPayload(workspaceResources *modconfig.ResourceMaps) ([]byte, error) {
+	return json.Marshal(buildAvailableDashboards(workspaceResources))
+}
 
+func buildAvailableDashboards(workspaceResources *modconfig.ResourceMaps) AvailableDashboardsPayload {
 	payload := AvailableDashboardsPayload{
 		Action:     "available_dashboards",
 		Dashboards: make(map[string]ModAvailableDashboard),
@@ -145,7 +153,7 @@ func buildAvailableDashboardsPayload(workspaceResources *modconfig.ResourceMaps)
 		}
 	}
 
-	return json.Marshal(payload)
+	return payload
 }
 
 func buildWorkspaceErrorPayload(e *dashboardevents.WorkspaceError) ([]byte, error) {
@@ -0,0 +1,52 @@
+package dashboardserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardexecute"
+)
+
+// refreshCheckInterval is how often the server checks whether any session is due a dashboard refresh
+const refreshCheckInterval = time.Second
+
+// startRefreshAsync periodically refreshes the dashboard selected by each session, if the dashboard has
+// a refresh interval (set by either the dashboard 'refresh' property or the 'refresh_interval' dashboard option)
+func (s *Server) startRefreshAsync(ctx context.Context) {
+	// read the option once, to avoid concurrent access to viper
+	defaultInterval := viper.GetInt(constants.ArgDashboardRefreshInterval)
+
+	go func() {
+		ticker := time.NewTicker(refreshCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for sessionId, dashboardName := range s.getSessionDashboards() {
+					if interval := s.getRefreshInterval(dashboardName, defaultInterval); interval > 0 {
+						// the executor only refreshes the dashboard if the current execution is complete and is due a refresh
+						go dashboardexecute.Executor.RefreshDashboard(ctx, sessionId, interval)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// getRefreshInterval returns the refresh interval of the dashboard, or zero if it should not be refreshed
+// (snapshots and benchmarks are never refreshed)
+func (s *Server) getRefreshInterval(dashboardName string, defaultInterval int) time.Duration {
+	dashboard, ok := s.workspace.GetResourceMaps().Dashboards[dashboardName]
+	if !ok {
+		return 0
+	}
+	seconds := defaultInterval
+	if dashboard.Refresh != nil {
+		seconds = *dashboard.Refresh
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,259 @@
+package dashboardserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardevents"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardexecute"
+	"github.com/turbot/steampipe/pkg/dashboard/dashboardtypes"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// the REST API allows dashboards and benchmarks to be listed and run without driving the websocket protocol
+
+const (
+	apiJobStatusRunning  = "running"
+	apiJobStatusComplete = "complete"
+	apiJobStatusError    = "error"
+
+	// the execution session id of an API job is this prefix followed by the job id
+	apiSessionPrefix = "api_"
+	// completed jobs are kept for this long so their results can be fetched
+	apiJobRetention = time.Hour
+)
+
+type apiRunRequest struct {
+	Inputs map[string]any `json:"inputs"`
+	// if set, return the job id immediately rather than waiting for the execution to complete
+	Async bool `json:"async"`
+}
+
+type apiErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// apiJob is an execution of a dashboard or benchmark started by the REST API
+type apiJob struct {
+	Id        string                            `json:"job_id"`
+	Dashboard string                            `json:"dashboard"`
+	Status    string                            `json:"status"`
+	Error     string                            `json:"error,omitempty"`
+	StartTime time.Time                         `json:"start_time"`
+	EndTime   *time.Time                        `json:"end_time,omitempty"`
+	Snapshot  *dashboardtypes.SteampipeSnapshot `json:"snapshot,omitempty"`
+	// the authenticated identity which started the job (empty if authentication is not enabled)
+	RequestedBy string `json:"requested_by,omitempty"`
+	// closed when the job completes
+	done chan struct{}
+}
+
+func (s *Server) registerAPIRoutes(ctx context.Context, router *gin.Engine) {
+	api := router.Group("/api/v1")
+	api.GET("/metadata", s.handleGetMetadata)
+	api.GET("/dashboards", s.handleListDashboards)
+	api.GET("/dashboards/:name", s.handleGetDashboard)
+	api.POST("/dashboards/:name/run", s.handleRunDashboard(ctx))
+	api.GET("/jobs/:id", s.handleGetJob)
+}
+
+func (s *Server) handleGetMetadata(c *gin.Context) {
+	c.JSON(http.StatusOK, buildDashboardMetadata(s.workspace.GetResourceMaps(), s.workspace.CloudMetadata))
+}
+
+func (s *Server) handleListDashboards(c *gin.Context) {
+	available := buildAvailableDashboards(s.workspace.GetResourceMaps())
+	c.JSON(http.StatusOK, gin.H{
+		"dashboards": available.Dashboards,
+		"benchmarks": available.Benchmarks,
+	})
+}
+
+func (s *Server) handleGetDashboard(c *gin.Context) {
+	name := c.Param("name")
+	available := buildAvailableDashboards(s.workspace.GetResourceMaps())
+	if dashboard, ok := available.Dashboards[name]; ok {
+		c.JSON(http.StatusOK, dashboard)
+		return
+	}
+	if benchmark, ok := available.Benchmarks[name]; ok {
+		c.JSON(http.StatusOK, benchmark)
+		return
+	}
+	c.JSON(http.StatusNotFound, apiErrorResponse{Error: fmt.Sprintf("dashboard or benchmark '%s' not found", name)})
+}
+
+// handleRunDashboard starts an execution of the dashboard using the server context,
+// so async executions are not cancelled when the request completes
+func (s *Server) handleRunDashboard(ctx context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// only accept json requests - a browser will not send these cross-site without a CORS preflight,
+		// so another web site cannot use the credentials of a logged-in user to start a run
+		if c.ContentType() != gin.MIMEJSON {
+			c.JSON(http.StatusUnsupportedMediaType, apiErrorResponse{Error: fmt.Sprintf("Content-Type must be %s", gin.MIMEJSON)})
+			return
+		}
+
+		name := c.Param("name")
+		available := buildAvailableDashboards(s.workspace.GetResourceMaps())
+		if _, ok := available.Dashboards[name]; !ok {
+			if _, ok := available.Benchmarks[name]; !ok {
+				c.JSON(http.StatusNotFound, apiErrorResponse{Error: fmt.Sprintf("dashboard or benchmark '%s' not found", name)})
+				return
+			}
+		}
+
+		var request apiRunRequest
+		// an empty json body runs the dashboard synchronously with no inputs
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&request); err != nil {
+				c.JSON(http.StatusBadRequest, apiErrorResponse{Error: fmt.Sprintf("invalid request body: %s", err.Error())})
+				return
+			}
+		}
+
+		job := s.addAPIJob(name, c.GetString(identityKey))
+		sessionId := apiSessionPrefix + job.Id
+		OutputWait(ctx, fmt.Sprintf("API execution started: %s", name))
+
+		// all inputs must be provided up front, as there is no way to provide them once the execution has started
+		err := dashboardexecute.Executor.ExecuteDashboardWithInputs(ctx, sessionId, name, request.Inputs, s.workspace, s.dbClient)
+		if err != nil {
+			status, err := apiExecutionError(err)
+			s.completeAPIJob(ctx, job.Id, nil, err)
+			c.JSON(status, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		if request.Async {
+			c.Header("Location", fmt.Sprintf("/api/v1/jobs/%s", job.Id))
+			c.JSON(http.StatusAccepted, gin.H{"job_id": job.Id, "status": apiJobStatusRunning})
+			return
+		}
+
+		select {
+		case <-job.done:
+		case <-c.Request.Context().Done():
+			// the client has gone away - there is no one to return the result to
+			// complete the job with an error (this also cancels the execution)
+			log.Printf("[TRACE] API request for job %s cancelled - cancelling execution", job.Id)
+			s.completeAPIJob(ctx, job.Id, nil, errors.New("the request was cancelled before the execution completed"))
+			return
+		case <-ctx.Done():
+			// the server is shutting down
+			s.completeAPIJob(ctx, job.Id, nil, errors.New("the server stopped before the execution completed"))
+			c.JSON(http.StatusServiceUnavailable, apiErrorResponse{Error: "the server stopped before the execution completed"})
+			return
+		}
+		completedJob, _ := s.getAPIJob(job.Id)
+		status := http.StatusOK
+		if completedJob.Status == apiJobStatusError {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, completedJob)
+	}
+}
+
+func (s *Server) handleGetJob(c *gin.Context) {
+	job, ok := s.getAPIJob(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, apiErrorResponse{Error: fmt.Sprintf("job '%s' not found", c.Param("id"))})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// handleAPIExecutionEvent completes the API job for the session of the event (if there is one)
+func (s *Server) handleAPIExecutionEvent(ctx context.Context, event dashboardevents.DashboardEvent) {
+	switch e := event.(type) {
+	case *dashboardevents.ExecutionComplete:
+		if jobId, ok := strings.CutPrefix(e.Session, apiSessionPrefix); ok {
+			s.completeAPIJob(ctx, jobId, dashboardexecute.ExecutionCompleteToSnapshot(e), nil)
+		}
+	case *dashboardevents.ExecutionError:
+		if jobId, ok := strings.CutPrefix(e.Session, apiSessionPrefix); ok {
+			_, err := apiExecutionError(e.Error)
+			s.completeAPIJob(ctx, jobId, nil, err)
+		}
+	}
+}
+
+// apiExecutionError returns the http status for an error starting an execution,
+// and rewords errors which refer to command line arguments
+func apiExecutionError(err error) (int, error) {
+	var missingInputsErr *dashboardexecute.MissingInputsError
+	if errors.As(err, &missingInputsErr) {
+		return http.StatusBadRequest, fmt.Errorf("values must be provided for %s: %s", utils.Pluralize("input", len(missingInputsErr.Inputs)), strings.Join(missingInputsErr.Inputs, ", "))
+	}
+	return http.StatusInternalServerError, err
+}
+
+// functions providing locked access to the API jobs
+
+func (s *Server) addAPIJob(dashboardName, identity string) *apiJob {
+	s.apiJobsLock.Lock()
+	defer s.apiJobsLock.Unlock()
+
+	// remove any jobs which completed more than apiJobRetention ago
+	for id, job := range s.apiJobs {
+		if job.EndTime != nil && time.Since(*job.EndTime) > apiJobRetention {
+			delete(s.apiJobs, id)
+		}
+	}
+
+	job := &apiJob{
+		Id:          uuid.New().String(),
+		Dashboard:   dashboardName,
+		RequestedBy: identity,
+		Status:      apiJobStatusRunning,
+		StartTime:   time.Now(),
+		done:        make(chan struct{}),
+	}
+	s.apiJobs[job.Id] = job
+	return job
+}
+
+// getAPIJob returns a copy of the job, so it may be serialised while the job is updated
+func (s *Server) getAPIJob(jobId string) (apiJob, bool) {
+	s.apiJobsLock.Lock()
+	defer s.apiJobsLock.Unlock()
+
+	job, ok := s.apiJobs[jobId]
+	if !ok {
+		return apiJob{}, false
+	}
+	return *job, true
+}
+
+// completeAPIJob sets the result of the job - only the first result is used,
+// as a failed execution may both return an error and publish an ExecutionError event
+func (s *Server) completeAPIJob(ctx context.Context, jobId string, snapshot *dashboardtypes.SteampipeSnapshot, err error) {
+	s.apiJobsLock.Lock()
+	defer s.apiJobsLock.Unlock()
+
+	job, ok := s.apiJobs[jobId]
+	if !ok || job.EndTime != nil {
+		return
+	}
+	endTime := time.Now()
+	job.EndTime = &endTime
+	if err != nil {
+		job.Status = apiJobStatusError
+		job.Error = err.Error()
+	} else {
+		job.Status = apiJobStatusComplete
+		job.Snapshot = snapshot
+	}
+	close(job.done)
+
+	// the execution is no longer needed - remove it from the executor
+	// (do this asynchronously as this may be called from the dashboard event handler)
+	go dashboardexecute.Executor.CancelExecutionForSession(ctx, apiSessionPrefix+jobId)
+}
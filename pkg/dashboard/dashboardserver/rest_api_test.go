@@ -0,0 +1,387 @@
+package dashboardserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/utils"
+	"github.com/turbot/steampipe/pkg/workspace"
+)
+
+func TestRunDashboardRequiresJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s := &Server{}
+	router.POST("/api/v1/dashboards/:name/run", s.handleRunDashboard(context.Background()))
+
+	// the content types a cross-site form or fetch may send without a CORS preflight
+	for _, contentType := range []string{"", "application/x-www-form-urlencoded", "multipart/form-data; boundary=x", "text/plain"} {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/dashboards/mod.dashboard.d1/run", strings.NewReader(`{"async":true}`))
+		if contentType != "" {
+			r.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("Content-Type '%s': expected status %d, got %d", contentType, http.StatusUnsupportedMediaType, w.Code)
+		}
+	}
+}
+
+const testModSource = `
+mod "test" {
+  title = "test"
+}
+
+dashboard "text" {
+  title = "Text Dashboard"
+  text {
+    value = "hello"
+  }
+}
+
+dashboard "with_input" {
+  title = "Input Dashboard"
+  input "region" {
+    sql = "select 1 as label, 1 as value"
+  }
+  table {
+    sql  = "select $1"
+    args = [self.input.region.value]
+  }
+}
+
+benchmark "empty" {
+  title    = "Empty Benchmark"
+  children = []
+}
+`
+
+// testDbClient is a db client for dashboards which do not run any queries
+type testDbClient struct {
+	db_common.Client
+}
+
+func (c *testDbClient) GetRequiredSessionSearchPath() []string {
+	return nil
+}
+
+func (c *testDbClient) GetCustomSearchPath() []string {
+	return nil
+}
+
+// testSearchPathDbClient is a db client with a custom search path
+// executions wait for the search path schemas, which calls acquire to get a management connection
+type testSearchPathDbClient struct {
+	testDbClient
+	acquire func(ctx context.Context) error
+}
+
+func (c *testSearchPathDbClient) GetCustomSearchPath() []string {
+	return []string{"aws"}
+}
+
+func (c *testSearchPathDbClient) AcquireManagementConnection(ctx context.Context) (*pgxpool.Conn, error) {
+	return nil, c.acquire(ctx)
+}
+
+// newTestAPIServer returns a router serving the REST API for a workspace containing the test mod
+func newTestAPIServer(t *testing.T, ctx context.Context) *gin.Engine {
+	_, router := newTestAPIServerWithClient(t, ctx, &testDbClient{})
+	return router
+}
+
+// newTestAPIServerWithClient returns the server and a router serving its REST API,
+// for a workspace containing the test mod and using the given db client
+func newTestAPIServerWithClient(t *testing.T, ctx context.Context, client db_common.Client) (*Server, *gin.Engine) {
+	modDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(modDir, "mod.sp"), []byte(testModSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set(constants.ConfigKeyBypassHomeDirModfileWarning, true)
+	t.Cleanup(viper.Reset)
+	w, errAndWarnings := workspace.Load(ctx, modDir)
+	if errAndWarnings.GetError() != nil {
+		t.Fatal(errAndWarnings.GetError())
+	}
+
+	s := &Server{
+		workspace:   w,
+		dbClient:    client,
+		apiJobs:     make(map[string]*apiJob),
+		apiJobsLock: &sync.Mutex{},
+	}
+	// only the API job handling is needed - there are no websocket clients
+	w.RegisterDashboardEventHandler(ctx, s.handleAPIExecutionEvent)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.registerAPIRoutes(ctx, router)
+	return s, router
+}
+
+// testJobResponse is the json response for an API job
+// (the snapshot panels cannot be unmarshalled into an apiJob)
+type testJobResponse struct {
+	Id        string     `json:"job_id"`
+	Dashboard string     `json:"dashboard"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error"`
+	EndTime   *time.Time `json:"end_time"`
+	Snapshot  *struct {
+		Panels map[string]json.RawMessage `json:"panels"`
+	} `json:"snapshot"`
+}
+
+// doAPIRequest makes a request to the router and decodes the json response into res
+func doAPIRequest(t *testing.T, router *gin.Engine, method, path, body string, res any) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if method == http.MethodPost {
+		r.Header.Set("Content-Type", gin.MIMEJSON)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if res != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+			t.Fatalf("%s %s: failed to decode response '%s': %v", method, path, w.Body.String(), err)
+		}
+	}
+	return w
+}
+
+func TestListAndGetDashboards(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	router := newTestAPIServer(t, ctx)
+
+	var list struct {
+		Dashboards map[string]struct {
+			Title string `json:"title"`
+		} `json:"dashboards"`
+		Benchmarks map[string]struct {
+			Title string `json:"title"`
+		} `json:"benchmarks"`
+	}
+	if w := doAPIRequest(t, router, http.MethodGet, "/api/v1/dashboards", "", &list); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if len(list.Dashboards) != 2 || list.Dashboards["test.dashboard.text"].Title != "Text Dashboard" || list.Dashboards["test.dashboard.with_input"].Title != "Input Dashboard" {
+		t.Errorf("unexpected dashboards: %+v", list.Dashboards)
+	}
+	if len(list.Benchmarks) != 1 || list.Benchmarks["test.benchmark.empty"].Title != "Empty Benchmark" {
+		t.Errorf("unexpected benchmarks: %+v", list.Benchmarks)
+	}
+
+	// both dashboards and benchmarks can be fetched by name
+	for name, title := range map[string]string{"test.dashboard.text": "Text Dashboard", "test.benchmark.empty": "Empty Benchmark"} {
+		var res struct {
+			FullName string `json:"full_name"`
+			Title    string `json:"title"`
+		}
+		if w := doAPIRequest(t, router, http.MethodGet, "/api/v1/dashboards/"+name, "", &res); w.Code != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", name, w.Code)
+		}
+		if res.FullName != name || res.Title != title {
+			t.Errorf("%s: unexpected response %+v", name, res)
+		}
+	}
+
+	var errorRes apiErrorResponse
+	if w := doAPIRequest(t, router, http.MethodGet, "/api/v1/dashboards/test.dashboard.missing", "", &errorRes); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+	if errorRes.Error != "dashboard or benchmark 'test.dashboard.missing' not found" {
+		t.Errorf("unexpected error: %s", errorRes.Error)
+	}
+}
+
+func TestRunDashboard(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	router := newTestAPIServer(t, ctx)
+
+	// synchronous runs return the completed job
+	var job testJobResponse
+	if w := doAPIRequest(t, router, http.MethodPost, "/api/v1/dashboards/test.dashboard.text/run", "", &job); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if job.Status != apiJobStatusComplete || job.Dashboard != "test.dashboard.text" || job.Snapshot == nil || job.EndTime == nil {
+		t.Errorf("unexpected job: %+v", job)
+	} else if _, ok := job.Snapshot.Panels["test.text.dashboard_text_anonymous_text_0"]; !ok {
+		t.Errorf("expected the snapshot to contain the text panel, got %v", utils.SortedMapKeys(job.Snapshot.Panels))
+	}
+
+	// async runs return the job id, which can be polled until the job is complete
+	var started struct {
+		JobId  string `json:"job_id"`
+		Status string `json:"status"`
+	}
+	w := doAPIRequest(t, router, http.MethodPost, "/api/v1/dashboards/test.dashboard.text/run", `{"async": true}`, &started)
+	if w.Code != http.StatusAccepted || started.Status != apiJobStatusRunning {
+		t.Fatalf("expected status 202 and a running job, got %d: %s", w.Code, w.Body.String())
+	}
+	if location := w.Header().Get("Location"); location != "/api/v1/jobs/"+started.JobId {
+		t.Errorf("unexpected Location header: %s", location)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		var polled testJobResponse
+		if w := doAPIRequest(t, router, http.MethodGet, "/api/v1/jobs/"+started.JobId, "", &polled); w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		if polled.Status == apiJobStatusComplete {
+			break
+		}
+		if polled.Status != apiJobStatusRunning || time.Now().After(deadline) {
+			t.Fatalf("expected the job to complete, got %+v", polled)
+		}
+	}
+}
+
+func TestRunDashboardInitialisationError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &testSearchPathDbClient{
+		acquire: func(context.Context) error { return errors.New("connection refused") },
+	}
+	_, router := newTestAPIServerWithClient(t, ctx, client)
+
+	// the execution fails before it starts - the synchronous run must return the error rather than wait forever
+	done := make(chan struct{})
+	var job testJobResponse
+	var w *httptest.ResponseRecorder
+	go func() {
+		defer close(done)
+		w = doAPIRequest(t, router, http.MethodPost, "/api/v1/dashboards/test.dashboard.text/run", "", &job)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run did not complete")
+	}
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+	if job.Status != apiJobStatusError || job.Error != "connection refused" || job.EndTime == nil {
+		t.Errorf("unexpected job: %+v", job)
+	}
+}
+
+func TestRunDashboardRequestCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the execution waits for the search path schemas until it is cancelled
+	acquired := make(chan struct{})
+	executionCancelled := make(chan struct{})
+	client := &testSearchPathDbClient{
+		acquire: func(ctx context.Context) error {
+			close(acquired)
+			<-ctx.Done()
+			close(executionCancelled)
+			return ctx.Err()
+		},
+	}
+	s, router := newTestAPIServerWithClient(t, ctx, client)
+
+	requestCtx, cancelRequest := context.WithCancel(ctx)
+	// (use a dashboard with a query, as a dashboard containing only text is complete before it executes)
+	body := `{"inputs": {"input.region": 1}}`
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/dashboards/test.dashboard.with_input/run", strings.NewReader(body)).WithContext(requestCtx)
+	r.Header.Set("Content-Type", gin.MIMEJSON)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}()
+
+	// the client goes away while the execution is running
+	<-acquired
+	cancelRequest()
+	for _, c := range []chan struct{}{done, executionCancelled} {
+		select {
+		case <-c:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the request to return and the execution to be cancelled")
+		}
+	}
+
+	// the job is completed with an error, so it is not left running
+	s.apiJobsLock.Lock()
+	var jobId string
+	for id := range s.apiJobs {
+		jobId = id
+	}
+	s.apiJobsLock.Unlock()
+	var job testJobResponse
+	doAPIRequest(t, router, http.MethodGet, "/api/v1/jobs/"+jobId, "", &job)
+	if job.Status != apiJobStatusError || job.Error != "the request was cancelled before the execution completed" || job.EndTime == nil {
+		t.Errorf("unexpected job: %+v", job)
+	}
+}
+
+func TestRunDashboardErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	router := newTestAPIServer(t, ctx)
+
+	type runErrorTest struct {
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}
+	tests := map[string]runErrorTest{
+		"missing dashboard": {
+			method:         http.MethodPost,
+			path:           "/api/v1/dashboards/test.dashboard.missing/run",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "dashboard or benchmark 'test.dashboard.missing' not found",
+		},
+		"invalid body": {
+			method:         http.MethodPost,
+			path:           "/api/v1/dashboards/test.dashboard.text/run",
+			body:           `{"inputs": []}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid request body: ",
+		},
+		// inputs cannot be provided once the execution has started, so the run is rejected
+		"missing inputs": {
+			method:         http.MethodPost,
+			path:           "/api/v1/dashboards/test.dashboard.with_input/run",
+			body:           `{"inputs": {}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "values must be provided for input: input.region",
+		},
+		"missing job": {
+			method:         http.MethodGet,
+			path:           "/api/v1/jobs/missing",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "job 'missing' not found",
+		},
+	}
+	for name, test := range tests {
+		var res apiErrorResponse
+		w := doAPIRequest(t, router, test.method, test.path, test.body, &res)
+		if w.Code != test.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d", name, test.expectedStatus, w.Code)
+		}
+		if !strings.HasPrefix(res.Error, test.expectedError) {
+			t.Errorf("%s: expected error '%s', got '%s'", name, test.expectedError, res.Error)
+		}
+	}
+}
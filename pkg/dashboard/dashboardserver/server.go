@@ -2,6 +2,7 @@ package dashboardserver
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"github.com/turbot/go-kit/helpers"
@@ -26,6 +27,13 @@ type Server struct {
 	dashboardClients map[string]*DashboardClientInfo
 	webSocket        *melody.Melody
 	workspace        *workspace.Workspace
+	// executions started by the REST API, keyed by job id
+	apiJobs     map[string]*apiJob
+	apiJobsLock *sync.Mutex
+	// if any authenticators are configured, all requests must be authenticated
+	authenticators []Authenticator
+	// if set, the server is served over https
+	tlsConfig *tls.Config
 }
 
 func NewServer(ctx context.Context, dbClient db_common.Client, w *workspace.Workspace) (*Server, error) {
@@ -33,7 +41,20 @@ func NewServer(ctx context.Context, dbClient db_common.Client, w *workspace.Work
 
 	OutputWait(ctx, "Starting Dashboard Server")
 
+	authenticators, err := newAuthenticators()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	webSocket := melody.New()
+	if len(authenticators) > 0 {
+		// melody accepts websocket requests from any origin by default
+		webSocket.Upgrader.CheckOrigin = checkSameOrigin
+	}
 
 	var dashboardClients = make(map[string]*DashboardClientInfo)
 
@@ -45,10 +66,14 @@ func NewServer(ctx context.Context, dbClient db_common.Client, w *workspace.Work
 		dashboardClients: dashboardClients,
 		webSocket:        webSocket,
 		workspace:        w,
+		apiJobs:          make(map[string]*apiJob),
+		apiJobsLock:      &sync.Mutex{},
+		authenticators:   authenticators,
+		tlsConfig:        tlsConfig,
 	}
 
 	w.RegisterDashboardEventHandler(ctx, server.HandleDashboardEvent)
-	err := w.SetupWatcher(ctx, dbClient, func(c context.Context, e error) {})
+	err = w.SetupWatcher(ctx, dbClient, func(c context.Context, e error) {})
 	OutputMessage(ctx, "Workspace loaded")
 
 	return server, err
@@ -58,7 +83,8 @@ func NewServer(ctx context.Context, dbClient db_common.Client, w *workspace.Work
 // it returns a channel which is signalled when the API server terminates
 func (s *Server) Start(ctx context.Context) chan struct{} {
 	s.initAsync(ctx)
-	return startAPIAsync(ctx, s.webSocket)
+	s.startRefreshAsync(ctx)
+	return startAPIAsync(ctx, s)
 }
 
 // Shutdown stops the API server
@@ -89,6 +115,9 @@ func (s *Server) HandleDashboardEvent(ctx context.Context, event dashboardevents
 		}
 	}()
 
+	// complete any REST API job this event is for
+	s.handleAPIExecutionEvent(ctx, event)
+
 	switch e := event.(type) {
 
 	case *dashboardevents.WorkspaceError:
@@ -107,7 +136,11 @@ func (s *Server) HandleDashboardEvent(ctx context.Context, event dashboardevents
 			return
 		}
 		s.writePayloadToSession(e.Session, payload)
-		OutputWait(ctx, fmt.Sprintf("Dashboard execution started: %s", e.Root.GetName()))
+		msg := fmt.Sprintf("Dashboard execution started: %s", e.Root.GetName())
+		if identity := s.getSessionIdentity(e.Session); identity != "" {
+			msg = fmt.Sprintf("%s (%s)", msg, identity)
+		}
+		OutputWait(ctx, msg)
 
 	case *dashboardevents.ExecutionError:
 		log.Println("[TRACE] execution error event")
@@ -397,6 +430,10 @@ func (s *Server) addSession(session *melody.Session) {
 	clientSession := &DashboardClientInfo{
 		Session: session,
 	}
+	// the identity is set when the websocket request is authenticated
+	if identity, ok := session.Get(identityKey); ok {
+		clientSession.Identity, _ = identity.(string)
+	}
 
 	s.addDashboardClient(sessionId, clientSession)
 }
@@ -425,6 +462,22 @@ func (s *Server) setDashboardForSession(sessionId string, dashboardName string,
 	return dashboardClientInfo
 }
 
+// getSessionIdentity returns the authenticated identity of the websocket session or API job with the given session id
+func (s *Server) getSessionIdentity(sessionId string) string {
+	if jobId, ok := strings.CutPrefix(sessionId, apiSessionPrefix); ok {
+		job, _ := s.getAPIJob(jobId)
+		return job.RequestedBy
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if sessionInfo, ok := s.dashboardClients[sessionId]; ok {
+		return sessionInfo.Identity
+	}
+	return ""
+}
+
 func (s *Server) writePayloadToSession(sessionId string, payload []byte) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -434,6 +487,20 @@ func (s *Server) writePayloadToSession(sessionId string, payload []byte) {
 	}
 }
 
+// getSessionDashboards returns a map of session id to the name of the dashboard selected by the session
+func (s *Server) getSessionDashboards() map[string]string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	res := make(map[string]string)
+	for sessionId, dashboardClientInfo := range s.dashboardClients {
+		if dashboardClientInfo.Dashboard != nil {
+			res[sessionId] = *dashboardClientInfo.Dashboard
+		}
+	}
+	return res
+}
+
 func (s *Server) getDashboardClients() map[string]*DashboardClientInfo {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
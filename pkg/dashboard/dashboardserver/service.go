@@ -35,6 +35,7 @@ type DashboardServiceState struct {
 	Port          int          `json:"port"`
 	ListenType    string       `json:"listen_type"`
 	Listen        []string     `json:"listen"`
+	TLS           bool         `json:"tls"`
 	StructVersion int64        `json:"struct_version"`
 }
 
@@ -131,27 +132,9 @@ func RunForService(ctx context.Context, serverListen ListenType, serverPort List
 	error_helpers.FailOnError(serverPort.IsValid())
 	error_helpers.FailOnError(serverListen.IsValid())
 
-	// NOTE: args must be specified <arg>=<arg val>, as each entry in this array is a separate arg passed to cobra
-	args := []string{
-		"dashboard",
-		fmt.Sprintf("--%s=%s", constants.ArgDashboardListen, string(serverListen)),
-		fmt.Sprintf("--%s=%d", constants.ArgDashboardPort, serverPort),
-		fmt.Sprintf("--%s=%s", constants.ArgInstallDir, filepaths.SteampipeDir),
-		fmt.Sprintf("--%s=%s", constants.ArgModLocation, viper.GetString(constants.ArgModLocation)),
-		fmt.Sprintf("--%s=true", constants.ArgServiceMode),
-		fmt.Sprintf("--%s=false", constants.ArgInput),
-	}
-
-	for _, variableArg := range viper.GetStringSlice(constants.ArgVariable) {
-		args = append(args, fmt.Sprintf("--%s=%s", constants.ArgVariable, variableArg))
-	}
-
-	for _, varFile := range viper.GetStringSlice(constants.ArgVarFile) {
-		args = append(args, fmt.Sprintf("--%s=%s", constants.ArgVarFile, varFile))
-	}
 	cmd := exec.Command(
 		self,
-		args...,
+		dashboardServiceArgs(serverListen, serverPort)...,
 	)
 	cmd.Env = os.Environ()
 
@@ -226,3 +209,28 @@ func WriteServiceStateFile(state *DashboardServiceState) error {
 	}
 	return os.WriteFile(filepaths.DashboardServiceStateFilePath(), stateBytes, 0666)
 }
+
+// dashboardServiceArgs returns the args of the 'steampipe dashboard' command run by the service
+func dashboardServiceArgs(serverListen ListenType, serverPort ListenPort) []string {
+	// NOTE: args must be specified <arg>=<arg val>, as each entry in this array is a separate arg passed to cobra
+	args := []string{
+		"dashboard",
+		fmt.Sprintf("--%s=%s", constants.ArgDashboardListen, string(serverListen)),
+		fmt.Sprintf("--%s=%d", constants.ArgDashboardPort, serverPort),
+		fmt.Sprintf("--%s=%s", constants.ArgInstallDir, filepaths.SteampipeDir),
+		fmt.Sprintf("--%s=%s", constants.ArgModLocation, viper.GetString(constants.ArgModLocation)),
+		fmt.Sprintf("--%s=true", constants.ArgServiceMode),
+		fmt.Sprintf("--%s=false", constants.ArgInput),
+		// the cache ttl may be set by either the service flag or the 'cache_ttl' dashboard option
+		fmt.Sprintf("--%s=%d", constants.ArgDashboardCacheTtl, viper.GetInt(constants.ArgDashboardCacheTtl)),
+	}
+
+	for _, variableArg := range viper.GetStringSlice(constants.ArgVariable) {
+		args = append(args, fmt.Sprintf("--%s=%s", constants.ArgVariable, variableArg))
+	}
+
+	for _, varFile := range viper.GetStringSlice(constants.ArgVarFile) {
+		args = append(args, fmt.Sprintf("--%s=%s", constants.ArgVarFile, varFile))
+	}
+	return args
+}
@@ -0,0 +1,31 @@
+package dashboardserver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/options"
+)
+
+func TestDashboardServiceArgsCacheTtl(t *testing.T) {
+	defer viper.Reset()
+
+	// the cache ttl is not shared by default
+	expected := fmt.Sprintf("--%s=0", constants.ArgDashboardCacheTtl)
+	if args := dashboardServiceArgs(ListenTypeLocal, 9194); !helpers.StringSliceContains(args, expected) {
+		t.Errorf("expected args to contain %s, got %v", expected, args)
+	}
+
+	// the 'cache_ttl' dashboard option is passed to the dashboard server
+	ttl := 300
+	for k, v := range (&options.GlobalDashboard{CacheTtl: &ttl}).ConfigMap() {
+		viper.Set(k, v)
+	}
+	expected = fmt.Sprintf("--%s=300", constants.ArgDashboardCacheTtl)
+	if args := dashboardServiceArgs(ListenTypeLocal, 9194); !helpers.StringSliceContains(args, expected) {
+		t.Errorf("expected args to contain %s, got %v", expected, args)
+	}
+}
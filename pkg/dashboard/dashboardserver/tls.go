@@ -0,0 +1,58 @@
+package dashboardserver
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+)
+
+// TLSEnabled returns whether the dashboard options configure the dashboard server to serve https
+func TLSEnabled() bool {
+	return viper.GetString(constants.ArgDashboardCertificateFile) != "" ||
+		viper.GetString(constants.ArgDashboardKeyFile) != "" ||
+		viper.GetBool(constants.ArgDashboardGenerateCertificate)
+}
+
+// URLScheme returns the scheme of the dashboard server url - https if TLS is enabled
+func URLScheme() string {
+	if TLSEnabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// newTLSConfig returns the TLS config for the certificate configured in the dashboard options
+// if TLS is not enabled, nil is returned
+func newTLSConfig() (*tls.Config, error) {
+	if !TLSEnabled() {
+		return nil, nil
+	}
+
+	certFile := viper.GetString(constants.ArgDashboardCertificateFile)
+	keyFile := viper.GetString(constants.ArgDashboardKeyFile)
+	switch {
+	case certFile != "" && keyFile != "":
+		// use the provided certificate
+	case certFile != "" || keyFile != "":
+		return nil, fmt.Errorf("both certificate_file and key_file must be set to serve dashboards over https")
+	default:
+		// generate_certificate is set - use a certificate signed by the steampipe root certificate
+		var err error
+		certFile, keyFile, err = db_local.EnsureDashboardServerCertificate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the dashboard server certificate: %s", err.Error())
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
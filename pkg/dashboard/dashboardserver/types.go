@@ -106,6 +106,8 @@ type DashboardClientInfo struct {
 	Session         *melody.Session
 	Dashboard       *string
 	DashboardInputs map[string]interface{}
+	// the authenticated identity of the client (empty if authentication is not enabled)
+	Identity string
 }
 
 type ClientRequestDashboardPayload struct {
@@ -7,22 +7,44 @@ import (
 type LeafData struct {
 	Columns []*queryresult.ColumnDef `json:"columns"`
 	Rows    []map[string]interface{} `json:"rows"`
+	// the row values in column order - Rows is keyed by column name so loses the values of duplicate column names
+	rowValues [][]interface{}
 }
 
 func NewLeafData(result *queryresult.SyncQueryResult) *LeafData {
 	leafData := &LeafData{
-		Rows:    make([]map[string]interface{}, len(result.Rows)),
-		Columns: result.Cols,
+		Rows:      make([]map[string]interface{}, len(result.Rows)),
+		Columns:   result.Cols,
+		rowValues: make([][]interface{}, len(result.Rows)),
 	}
 
 	for rowIdx, row := range result.Rows {
+		rowValues := row.(*queryresult.RowResult).Data
 		rowData := make(map[string]interface{}, len(result.Cols))
-		for i, data := range row.(*queryresult.RowResult).Data {
+		for i, data := range rowValues {
 			columnName := leafData.Columns[i].Name
 			rowData[columnName] = data
 		}
 
 		leafData.Rows[rowIdx] = rowData
+		leafData.rowValues[rowIdx] = rowValues
 	}
 	return leafData
 }
+
+// RowValues returns the values of each row in column order
+func (d *LeafData) RowValues() [][]interface{} {
+	if d.rowValues != nil {
+		return d.rowValues
+	}
+	// the leaf data was not created from a query result, so read the values from the rows by column name
+	rowValues := make([][]interface{}, len(d.Rows))
+	for rowIdx, row := range d.Rows {
+		values := make([]interface{}, len(d.Columns))
+		for i, col := range d.Columns {
+			values[i] = row[col.Name]
+		}
+		rowValues[rowIdx] = values
+	}
+	return rowValues
+}
@@ -57,8 +57,12 @@ type DbClient struct {
 	// a cached copy of (viper.GetBool(constants.ArgTiming) && viper.GetString(constants.ArgOutput) == constants.OutputFormatTable)
 	// (cached to avoid concurrent access error on viper)
 	showTimingFlag bool
-	// disable timing - set whilst in process of querying the timing
-	disableTiming        bool
+	// a cached copy of viper.GetBool(constants.ArgExplain)
+	showExplainFlag bool
+	// protects showTimingFlag and showExplainFlag, as queries may be executed concurrently
+	timingFlagLock *sync.Mutex
+	// if set, every query is recorded in the query metrics table for the service metrics
+	queryMetricsEnabled  bool
 	onConnectionCallback DbConnectionCallback
 }
 
@@ -83,6 +87,7 @@ func NewDbClient(ctx context.Context, connectionString string, onConnectionCallb
 		parallelSessionInitLock: semaphore.NewWeighted(constants.MaxParallelClientInits),
 		sessions:                make(map[uint32]*db_common.DatabaseSession),
 		sessionsMutex:           &sync.Mutex{},
+		timingFlagLock:          &sync.Mutex{},
 		// store the callback
 		onConnectionCallback: wrappedOnConnectionCallback,
 		connectionString:     connectionString,
@@ -99,6 +104,7 @@ func NewDbClient(ctx context.Context, connectionString string, onConnectionCallb
 	for _, o := range opts {
 		o(&config)
 	}
+	client.queryMetricsEnabled = config.recordQueryMetrics
 
 	if err := client.establishConnectionPool(ctx, config); err != nil {
 		return nil, err
@@ -150,18 +156,31 @@ func (c *DbClient) loadServerSettings(ctx context.Context) error {
 
 func (c *DbClient) setShouldShowTiming(ctx context.Context, session *db_common.DatabaseSession) {
 	currentShowTimingFlag := viper.GetBool(constants.ArgTiming)
+	currentShowExplainFlag := viper.GetBool(constants.ArgExplain)
 
-	// if we are turning timing ON, fetch the ScanMetadataMaxId
+	c.timingFlagLock.Lock()
+	turningOn := (currentShowTimingFlag || currentShowExplainFlag) && !(c.showTimingFlag || c.showExplainFlag)
+	c.showTimingFlag = currentShowTimingFlag
+	c.showExplainFlag = currentShowExplainFlag
+	c.timingFlagLock.Unlock()
+
+	// if we are turning timing or explain ON, fetch the ScanMetadataMaxId
 	// to ensure we only select the relevant scan metadata table entries
-	if currentShowTimingFlag && !c.showTimingFlag {
+	if turningOn {
 		c.updateScanMetadataMaxId(ctx, session)
 	}
-
-	c.showTimingFlag = currentShowTimingFlag
 }
 
 func (c *DbClient) shouldShowTiming() bool {
-	return c.showTimingFlag && !c.disableTiming
+	c.timingFlagLock.Lock()
+	defer c.timingFlagLock.Unlock()
+	return c.showTimingFlag
+}
+
+func (c *DbClient) shouldShowExplain() bool {
+	c.timingFlagLock.Lock()
+	defer c.timingFlagLock.Unlock()
+	return c.showExplainFlag
 }
 
 // ServerSettings returns the settings of the steampipe service that this DbClient is connected to
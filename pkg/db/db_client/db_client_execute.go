@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log"
 	"net/netip"
 	"strings"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/db/db_common"
 	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/introspection"
 	"github.com/turbot/steampipe/pkg/query/queryresult"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/utils"
@@ -95,14 +97,21 @@ func (c *DbClient) Execute(ctx context.Context, query string, args ...any) (*que
 
 	// define callback to close session when the async execution is complete
 	closeSessionCallback := func() { sessionResult.Session.Close(error_helpers.IsContextCanceled(ctx)) }
-	return c.ExecuteInSession(ctx, sessionResult.Session, closeSessionCallback, query, args...)
+	// the row limit only applies to queries run by the query command, so it is not applied by ExecuteInSession
+	maxRows := viper.GetInt(constants.ArgMaxRows)
+	return c.executeInSession(ctx, sessionResult.Session, closeSessionCallback, maxRows, query, args...)
 }
 
 // ExecuteInSession implements Client
 // execute the query in the given Context using the provided DatabaseSession
 // ExecuteInSession assumes no responsibility over the lifecycle of the DatabaseSession - that is the responsibility of the caller
 // NOTE: The returned Result MUST be fully read - otherwise the connection will block and will prevent further communication
-func (c *DbClient) ExecuteInSession(ctx context.Context, session *db_common.DatabaseSession, onComplete func(), query string, args ...any) (res *queryresult.Result, err error) {
+func (c *DbClient) ExecuteInSession(ctx context.Context, session *db_common.DatabaseSession, onComplete func(), query string, args ...any) (*queryresult.Result, error) {
+	return c.executeInSession(ctx, session, onComplete, 0, query, args...)
+}
+
+// if maxRows is non-zero, only the first maxRows rows are returned and the query is cancelled
+func (c *DbClient) executeInSession(ctx context.Context, session *db_common.DatabaseSession, onComplete func(), maxRows int, query string, args ...any) (res *queryresult.Result, err error) {
 	if query == "" {
 		return queryresult.NewResult(nil), nil
 	}
@@ -154,11 +163,23 @@ func (c *DbClient) ExecuteInSession(ctx context.Context, session *db_common.Data
 		// define a callback which fetches the timing information
 		// this will be invoked after reading rows is complete but BEFORE closing the rows object (which closes the connection)
 		timingCallback := func() {
-			c.getQueryTiming(ctxExecute, startTime, session, result.TimingResult)
+			duration := time.Since(startTime)
+			// the scans made by the query are those after the current max id
+			// (read it first, as fetching the timing moves the max id past the scans of this query)
+			scanMetadataMaxId := session.ScanMetadataMaxId
+			c.getQueryTiming(ctxExecute, startTime, session, result.TimingResult, query, args...)
+			if c.queryMetricsEnabled {
+				c.recordQueryMetrics(ctxExecute, session, duration, scanMetadataMaxId)
+			}
+		}
+
+		// define a callback which cancels the query if the result is truncated
+		cancelCallback := func() {
+			cancelQuery(ctxExecute, rows)
 		}
 
 		// read in the rows and stream to the query result object
-		c.readRows(ctxExecute, rows, result, timingCallback)
+		c.readRows(ctxExecute, rows, result, maxRows, cancelCallback, timingCallback)
 
 		// call the completion callback - if one was provided
 		if onComplete != nil {
@@ -183,31 +204,38 @@ func (c *DbClient) getExecuteContext(ctx context.Context) context.Context {
 	return newCtx
 }
 
-func (c *DbClient) getQueryTiming(ctx context.Context, startTime time.Time, session *db_common.DatabaseSession, resultChannel chan *queryresult.TimingResult) {
-	if !c.shouldShowTiming() {
+// in explain mode, the query and args are used to fetch the query plan
+func (c *DbClient) getQueryTiming(ctx context.Context, startTime time.Time, session *db_common.DatabaseSession, resultChannel chan *queryresult.TimingResult, query string, args ...any) {
+	showTiming := c.shouldShowTiming()
+	showExplain := c.shouldShowExplain()
+	if !showTiming && !showExplain {
 		return
 	}
 
 	var timingResult = &queryresult.TimingResult{
 		Duration: time.Since(startTime),
 	}
-	// disable fetching timing information to avoid recursion
-	c.disableTiming = true
-
-	// whatever happens, we need to reenable timing, and send the result back with at least the duration
+	// whatever happens, we need to send the result back with at least the duration
+	// NOTE: the scan metadata is read directly on the session connection, so reading it does not itself record any timing
+	// (this function may be running for several queries at once, so it must not change any client state)
 	defer func() {
-		c.disableTiming = false
 		resultChannel <- timingResult
 	}()
 
+	// the scans made by the query are those after the current max id
+	scanMetadataMaxId := session.ScanMetadataMaxId
+	if showExplain {
+		timingResult.Explain = c.getExplainResult(ctx, session, scanMetadataMaxId, query, args...)
+	}
+
 	var scanRows *ScanMetadataRow
 	err := db_common.ExecuteSystemClientCall(ctx, session.Connection.Conn(), func(ctx context.Context, tx pgx.Tx) error {
-		query := fmt.Sprintf("select id, rows_fetched, cache_hit, hydrate_calls from %s.%s where id > %d", constants.InternalSchema, constants.ForeignTableScanMetadata, session.ScanMetadataMaxId)
+		query := fmt.Sprintf("select id, rows_fetched, cache_hit, hydrate_calls from %s.%s where id > %d", constants.InternalSchema, constants.ForeignTableScanMetadata, scanMetadataMaxId)
 		rows, err := tx.Query(ctx, query)
 		if err != nil {
 			return err
 		}
-		scanRows, err = pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[ScanMetadataRow])
+		scanRows, err = pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByNameLax[ScanMetadataRow])
 		return err
 	})
 
@@ -225,8 +253,66 @@ func (c *DbClient) getQueryTiming(ctx context.Context, startTime time.Time, sess
 	} else {
 		timingResult.Metadata.RowsFetched += scanRows.RowsFetched
 	}
-	// update the max id for this session
-	session.ScanMetadataMaxId = scanRows.Id
+	// update the max id for this session (getExplainResult may already have moved it past this scan)
+	session.ScanMetadataMaxId = max(session.ScanMetadataMaxId, scanRows.Id)
+}
+
+// getExplainResult fetches the Postgres query plan and the metadata of every foreign table scan made by the query
+// failures are logged rather than returned - the query itself has succeeded
+func (c *DbClient) getExplainResult(ctx context.Context, session *db_common.DatabaseSession, scanMetadataMaxId int64, query string, args ...any) *queryresult.ExplainResult {
+	res := &queryresult.ExplainResult{}
+	err := db_common.ExecuteSystemClientCall(ctx, session.Connection.Conn(), func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, fmt.Sprintf("select * from %s.%s where id > %d order by id", constants.InternalSchema, constants.ForeignTableScanMetadata, scanMetadataMaxId))
+		if err != nil {
+			return err
+		}
+		scanRows, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByNameLax[ScanMetadataRow])
+		if err != nil {
+			return err
+		}
+		for _, scanRow := range scanRows {
+			res.Scans = append(res.Scans, scanRow.AsScanMetadata())
+			session.ScanMetadataMaxId = max(session.ScanMetadataMaxId, scanRow.Id)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[WARN] failed to read scan metadata: %s", err.Error())
+	}
+
+	// NOTE: EXPLAIN (without ANALYZE) only plans the query - it does not run it again
+	err = db_common.ExecuteSystemClientCall(ctx, session.Connection.Conn(), func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, "explain "+query, args...)
+		if err != nil {
+			return err
+		}
+		res.Plan, err = pgx.CollectRows(rows, pgx.RowTo[string])
+		return err
+	})
+	if err != nil {
+		// some statements cannot be explained, e.g. DDL and multiple statements
+		log.Printf("[WARN] failed to explain query: %s", err.Error())
+	}
+	return res
+}
+
+// recordQueryMetrics records the duration of a query, and the scans it made, in the query metrics table
+// (the plugin manager consumes these records when the service metrics are scraped)
+// failures are logged rather than returned - the query itself has succeeded
+func (c *DbClient) recordQueryMetrics(ctx context.Context, session *db_common.DatabaseSession, duration time.Duration, scanMetadataMaxId int64) {
+	insert := introspection.GetQueryMetricsInsertSql(duration, scanMetadataMaxId)
+	err := db_common.ExecuteSystemClientCall(ctx, session.Connection.Conn(), func(ctx context.Context, tx pgx.Tx) error {
+		var maxId int64
+		if err := tx.QueryRow(ctx, insert.Query, insert.Args...).Scan(&maxId); err != nil {
+			return err
+		}
+		// move the session past the recorded scans, so they are not recorded again by the next query
+		session.ScanMetadataMaxId = max(session.ScanMetadataMaxId, maxId)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[WARN] failed to record query metrics: %s", err.Error())
+	}
 }
 
 func (c *DbClient) updateScanMetadataMaxId(ctx context.Context, session *db_common.DatabaseSession) error {
@@ -258,7 +344,10 @@ func (c *DbClient) startQuery(ctx context.Context, conn *pgx.Conn, query string,
 	return
 }
 
-func (c *DbClient) readRows(ctx context.Context, rows pgx.Rows, result *queryresult.Result, timingCallback func()) {
+// if maxRows is non-zero and the query returns more rows than this, the result is truncated and the query is cancelled
+// using cancelCallback, which must also close the rows
+// (unless the max-rows confirmation function of the context chooses to fetch the remaining rows)
+func (c *DbClient) readRows(ctx context.Context, rows pgx.Rows, result *queryresult.Result, maxRows int, cancelCallback func(), timingCallback func()) {
 	// defer this, so that these get cleaned up even if there is an unforeseen error
 	defer func() {
 		// we are done fetching results. time for display. clear the status indication
@@ -267,7 +356,8 @@ func (c *DbClient) readRows(ctx context.Context, rows pgx.Rows, result *queryres
 		timingCallback()
 		// close the sql rows object
 		rows.Close()
-		if err := rows.Err(); err != nil {
+		// if we truncated the result, the cancellation error is expected
+		if err := rows.Err(); err != nil && !(result.Truncated && db_common.IsQueryCanceledError(err)) {
 			result.StreamError(err)
 		}
 		// close the channels in the result object
@@ -283,6 +373,16 @@ Loop:
 			statushooks.SetStatus(ctx, "Cancelling query")
 			break Loop
 		default:
+			if maxRows > 0 && rowCount >= maxRows {
+				// if the context has a confirmation function (e.g. in interactive mode), it may choose to fetch the remaining rows
+				if confirm := db_common.MaxRowsConfirmFromContext(ctx); confirm != nil && confirm(ctx, maxRows) {
+					maxRows = 0
+				} else {
+					result.Truncated = true
+					cancelCallback()
+					break Loop
+				}
+			}
 			rowResult, err := readRow(rows, result.Cols)
 			if err != nil {
 				// the error will be streamed in the defer
@@ -305,6 +405,31 @@ Loop:
 	}
 }
 
+// cancelQuery cancels the query which is returning the rows, so no more rows are fetched from the plugins
+// the rows are then closed, as the timing callback must not run queries on the connection until the query is complete
+func cancelQuery(ctx context.Context, rows pgx.Rows) {
+	statushooks.SetStatus(ctx, "Cancelling query")
+	conn := rows.Conn()
+	if err := conn.PgConn().CancelRequest(ctx); err != nil {
+		log.Printf("[WARN] failed to cancel truncated query: %s", err.Error())
+		rows.Close()
+		return
+	}
+	rows.Close()
+
+	// if the query was cancelled, the cancel request has been handled and the connection can be reused
+	// however if the query completed before the cancel request reached the server, the request may still
+	// cancel a later query on this connection (such as the timing query) - so close the connection,
+	// which stops it being returned to the pool
+	// (the timing query will then fail, so only the duration of the query is reported)
+	if !db_common.IsQueryCanceledError(rows.Err()) {
+		log.Printf("[TRACE] truncated query completed before it was cancelled - closing the connection")
+		if err := conn.Close(ctx); err != nil {
+			log.Printf("[WARN] failed to close connection: %s", err.Error())
+		}
+	}
+}
+
 func readRow(rows pgx.Rows, cols []*queryresult.ColumnDef) ([]interface{}, error) {
 	columnValues, err := rows.Values()
 	if err != nil {
@@ -375,7 +500,7 @@ func populateRow(columnValues []interface{}, cols []*queryresult.ColumnDef) ([]i
 func isStreamingOutput() bool {
 	outputFormat := viper.GetString(constants.ArgOutput)
 
-	return helpers.StringSliceContains([]string{constants.OutputFormatCSV, constants.OutputFormatLine}, outputFormat)
+	return helpers.StringSliceContains([]string{constants.OutputFormatCSV, constants.OutputFormatLine, constants.OutputFormatJSONL, constants.OutputFormatMarkdown, constants.OutputFormatHTML, constants.OutputFormatYAML}, outputFormat)
 }
 
 func humanizeRowCount(count int) string {
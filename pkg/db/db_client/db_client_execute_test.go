@@ -0,0 +1,178 @@
+package db_client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// testRows is a pgx.Rows which returns rowCount rows, each containing the row index
+type testRows struct {
+	pgx.Rows
+	rowCount int
+	index    int
+	err      error
+	closed   bool
+}
+
+func (r *testRows) Next() bool {
+	if r.closed || r.index >= r.rowCount {
+		return false
+	}
+	r.index++
+	return true
+}
+
+func (r *testRows) Values() ([]any, error) {
+	return []any{int64(r.index - 1)}, nil
+}
+
+func (r *testRows) Err() error {
+	return r.err
+}
+
+func (r *testRows) Close() {
+	r.closed = true
+}
+
+func TestReadRowsMaxRows(t *testing.T) {
+	queryCanceledError := &pgconn.PgError{Code: "57014", Message: "canceling statement due to user request"}
+
+	type readRowsTest struct {
+		rowCount int
+		maxRows  int
+		// the error returned by the rows once closed
+		err              error
+		expectedRows     int
+		expectTruncated  bool
+		expectedRowError error
+		// if set, the response of the max-rows confirmation
+		confirm *bool
+	}
+	yes, no := true, false
+	tests := map[string]readRowsTest{
+		"no limit": {
+			rowCount:     10,
+			expectedRows: 10,
+		},
+		"under limit": {
+			rowCount:     10,
+			maxRows:      10,
+			expectedRows: 10,
+		},
+		"over limit": {
+			rowCount:        10,
+			maxRows:         3,
+			err:             queryCanceledError,
+			expectedRows:    3,
+			expectTruncated: true,
+		},
+		"over limit, query completed before cancel": {
+			rowCount:        4,
+			maxRows:         3,
+			expectedRows:    3,
+			expectTruncated: true,
+		},
+		"over limit, confirmed": {
+			rowCount:     10,
+			maxRows:      3,
+			confirm:      &yes,
+			expectedRows: 10,
+		},
+		"over limit, declined": {
+			rowCount:        10,
+			maxRows:         3,
+			confirm:         &no,
+			err:             queryCanceledError,
+			expectedRows:    3,
+			expectTruncated: true,
+		},
+		// the cancellation error is only expected if the result was truncated
+		"cancelled without limit": {
+			rowCount:         10,
+			err:              queryCanceledError,
+			expectedRows:     10,
+			expectedRowError: queryCanceledError,
+		},
+		"over limit, other error": {
+			rowCount:         10,
+			maxRows:          3,
+			err:              errors.New("connection reset"),
+			expectedRows:     3,
+			expectTruncated:  true,
+			expectedRowError: errors.New("connection reset"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rows := &testRows{rowCount: test.rowCount, err: test.err}
+			result := queryresult.NewResult([]*queryresult.ColumnDef{{Name: "id", DataType: "INT8"}})
+
+			cancelCount := 0
+			cancelCallback := func() {
+				cancelCount++
+				rows.Close()
+			}
+			timingCalled := false
+			timingCallback := func() {
+				// the query must be complete before the timing is fetched
+				if cancelCount > 0 && !rows.closed {
+					t.Errorf("timing fetched before the cancelled query was closed")
+				}
+				timingCalled = true
+			}
+			ctx := context.Background()
+			confirmCount := 0
+			if test.confirm != nil {
+				ctx = db_common.AddMaxRowsConfirmToContext(ctx, func(_ context.Context, maxRows int) bool {
+					confirmCount++
+					if maxRows != test.maxRows {
+						t.Errorf("expected confirmation for %d rows, got %d", test.maxRows, maxRows)
+					}
+					return *test.confirm
+				})
+			}
+			go func() {
+				(&DbClient{}).readRows(ctx, rows, result, test.maxRows, cancelCallback, timingCallback)
+			}()
+
+			var rowCount int
+			var rowError error
+			for row := range *result.RowChan {
+				if row.Error != nil {
+					rowError = row.Error
+					continue
+				}
+				if row.Data[0] != int64(rowCount) {
+					t.Errorf("expected row %d, got %v", rowCount, row.Data[0])
+				}
+				rowCount++
+			}
+
+			if rowCount != test.expectedRows {
+				t.Errorf("expected %d rows, got %d", test.expectedRows, rowCount)
+			}
+			if result.Truncated != test.expectTruncated {
+				t.Errorf("expected truncated to be %v", test.expectTruncated)
+			}
+			if expected := map[bool]int{true: 1, false: 0}[test.expectTruncated]; cancelCount != expected {
+				t.Errorf("expected the query to be cancelled %d times, got %d", expected, cancelCount)
+			}
+			if (rowError == nil) != (test.expectedRowError == nil) || (rowError != nil && rowError.Error() != test.expectedRowError.Error()) {
+				t.Errorf("expected row error %v, got %v", test.expectedRowError, rowError)
+			}
+			if test.confirm != nil && confirmCount != 1 {
+				t.Errorf("expected the remaining rows to be confirmed once, got %d", confirmCount)
+			}
+			if !timingCalled {
+				t.Errorf("expected the timing callback to be called")
+			}
+		})
+	}
+}
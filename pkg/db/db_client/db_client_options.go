@@ -28,6 +28,7 @@ func (c PoolOverrides) apply(config *pgxpool.Config) {
 type clientConfig struct {
 	userPoolSettings       PoolOverrides
 	managementPoolSettings PoolOverrides
+	recordQueryMetrics     bool
 }
 
 type ClientOption func(*clientConfig)
@@ -43,3 +44,11 @@ func WithManagementPoolOverride(s PoolOverrides) ClientOption {
 		cc.managementPoolSettings = s
 	}
 }
+
+// WithQueryMetrics causes the client to record every query it executes for the service metrics
+// (this is used when the service is serving metrics)
+func WithQueryMetrics() ClientOption {
+	return func(cc *clientConfig) {
+		cc.recordQueryMetrics = true
+	}
+}
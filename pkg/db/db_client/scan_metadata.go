@@ -1,9 +1,61 @@
 package db_client
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
 type ScanMetadataRow struct {
 	// the fields of this struct need to be public since these are populated by pgx using RowsToStruct
 	Id           int64 `db:"id"`
 	RowsFetched  int64 `db:"rows_fetched"`
 	CacheHit     bool  `db:"cache_hit"`
 	HydrateCalls int64 `db:"hydrate_calls"`
+	// the following are only read in explain mode
+	// (they are nullable as older versions of the FDW may not populate them)
+	Connection *string    `db:"connection"`
+	Table      *string    `db:"table"`
+	StartTime  *time.Time `db:"start_time"`
+	// the scan duration in milliseconds
+	Duration *float64 `db:"duration"`
+	// the columns requested from the plugin, a jsonb array of column names
+	Columns any `db:"columns"`
+	// the limit pushed down to the plugin, -1 if there was no limit
+	Limit *int64  `db:"limit"`
+	Quals *string `db:"quals"`
+}
+
+func (r *ScanMetadataRow) AsScanMetadata() *queryresult.ScanMetadata {
+	res := &queryresult.ScanMetadata{
+		RowsFetched:  r.RowsFetched,
+		CacheHit:     r.CacheHit,
+		HydrateCalls: r.HydrateCalls,
+	}
+	if r.Connection != nil {
+		res.Connection = *r.Connection
+	}
+	if r.Table != nil {
+		res.Table = *r.Table
+	}
+	if r.Quals != nil {
+		res.Quals = *r.Quals
+	}
+	if r.StartTime != nil {
+		res.StartTime = *r.StartTime
+	}
+	if r.Duration != nil {
+		res.Duration = time.Duration(*r.Duration * float64(time.Millisecond))
+	}
+	if r.Limit != nil && *r.Limit >= 0 {
+		limit := *r.Limit
+		res.Limit = &limit
+	}
+	if columns, ok := r.Columns.([]any); ok {
+		for _, c := range columns {
+			res.Columns = append(res.Columns, fmt.Sprintf("%v", c))
+		}
+	}
+	return res
 }
@@ -0,0 +1,67 @@
+package db_client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+func TestAsScanMetadata(t *testing.T) {
+	connection := "aws"
+	table := "aws_s3_bucket"
+	quals := "region = 'us-east-1'"
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	duration := 1.5
+	limit := int64(5)
+	noLimit := int64(-1)
+
+	tests := map[string]struct {
+		row      ScanMetadataRow
+		expected queryresult.ScanMetadata
+	}{
+		"all fields": {
+			row: ScanMetadataRow{
+				RowsFetched:  10,
+				CacheHit:     true,
+				HydrateCalls: 20,
+				Connection:   &connection,
+				Table:        &table,
+				StartTime:    &start,
+				Duration:     &duration,
+				// jsonb arrays are returned as []any
+				Columns: []any{"name", "arn"},
+				Limit:   &limit,
+				Quals:   &quals,
+			},
+			expected: queryresult.ScanMetadata{
+				Connection:   connection,
+				Table:        table,
+				Quals:        quals,
+				Columns:      []string{"name", "arn"},
+				Limit:        &limit,
+				RowsFetched:  10,
+				CacheHit:     true,
+				HydrateCalls: 20,
+				StartTime:    start,
+				Duration:     1500 * time.Microsecond,
+			},
+		},
+		"no limit": {
+			row:      ScanMetadataRow{Limit: &noLimit},
+			expected: queryresult.ScanMetadata{},
+		},
+		"null fields": {
+			row:      ScanMetadataRow{RowsFetched: 1},
+			expected: queryresult.ScanMetadata{RowsFetched: 1},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.row.AsScanMetadata(); !reflect.DeepEqual(*got, test.expected) {
+				t.Errorf("AsScanMetadata() = %+v, expected %+v", *got, test.expected)
+			}
+		})
+	}
+}
@@ -36,3 +36,9 @@ func GetMissingSchemaFromIsRelationNotFoundError(err error) (string, string, boo
 	}
 	return "", "", true
 }
+
+// IsQueryCanceledError returns whether the error is the Postgres 'canceling statement due to user request' error
+func IsQueryCanceledError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "57014"
+}
@@ -42,9 +42,9 @@ func (r *InitResult) DisplayMessages() {
 			error_helpers.ShowWarning(w)
 		}
 	}
-	// do not display message in json or csv output mode
+	// do not display message in json, jsonl, yaml or csv output mode
 	output := viper.Get(constants.ArgOutput)
-	if output == constants.OutputFormatJSON || output == constants.OutputFormatCSV {
+	if output == constants.OutputFormatJSON || output == constants.OutputFormatJSONL || output == constants.OutputFormatYAML || output == constants.OutputFormatCSV {
 		return
 	}
 	for _, w := range r.Warnings {
@@ -0,0 +1,30 @@
+package db_common
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe/pkg/contexthelpers"
+)
+
+var contextKeyMaxRowsConfirm = contexthelpers.ContextKey("max_rows_confirm")
+
+// MaxRowsConfirmFunc is called when a query has returned the max-rows limit and has more rows
+// if it returns true the remaining rows are fetched, otherwise the result is truncated
+type MaxRowsConfirmFunc func(ctx context.Context, maxRows int) bool
+
+// AddMaxRowsConfirmToContext adds a function to the context which is asked whether to fetch the remaining rows of
+// a query which exceeds the max-rows limit (e.g. by prompting the user)
+func AddMaxRowsConfirmToContext(ctx context.Context, confirm MaxRowsConfirmFunc) context.Context {
+	return context.WithValue(ctx, contextKeyMaxRowsConfirm, confirm)
+}
+
+// MaxRowsConfirmFromContext returns the max-rows confirmation function of the context, or nil if there is none
+func MaxRowsConfirmFromContext(ctx context.Context) MaxRowsConfirmFunc {
+	if ctx == nil {
+		return nil
+	}
+	if val, ok := ctx.Value(contextKeyMaxRowsConfirm).(MaxRowsConfirmFunc); ok {
+		return val
+	}
+	return nil
+}
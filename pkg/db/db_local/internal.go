@@ -247,6 +247,14 @@ func initializeConnectionStateTable(ctx context.Context, conn *pgx.Conn) error {
 	return err
 }
 
+// initializeCheckResultTables creates the tables used to store check run history (if they do not already exist)
+func initializeCheckResultTables(ctx context.Context, conn *pgx.Conn) error {
+	queries := introspection.GetCheckResultTablesCreateSql()
+	queries = append(queries, introspection.GetCheckResultTablesGrantSql()...)
+	_, err := ExecuteSqlWithArgsInTransaction(ctx, conn, queries...)
+	return err
+}
+
 func PopulatePluginTable(ctx context.Context, conn *pgx.Conn) error {
 	plugins := steampipeconfig.GlobalConfig.PluginsInstances
 
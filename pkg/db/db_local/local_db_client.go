@@ -44,6 +44,11 @@ func GetLocalClient(ctx context.Context, invoker constants.Invoker, onConnection
 		return nil, &startResult.ErrorAndWarnings
 	}
 
+	// if the service is serving metrics, record the queries of this client for them
+	if startResult.PluginManagerState != nil && startResult.PluginManagerState.MetricsListen != "" {
+		opts = append(opts, db_client.WithQueryMetrics())
+	}
+
 	log.Printf("[INFO] newLocalClient")
 	client, err := newLocalClient(ctx, invoker, onConnectionCallback, opts...)
 	if err != nil {
@@ -65,6 +70,17 @@ func GetLocalClient(ctx context.Context, invoker constants.Invoker, onConnection
 	return client, &startResult.ErrorAndWarnings
 }
 
+// GetServiceClient returns a client for the running service, without starting it
+// this is used by the service itself, e.g. to execute scheduled runs
+// NOTE: unlike a LocalDbClient, closing this client does not shut down the service
+func GetServiceClient(ctx context.Context, opts ...db_client.ClientOption) (*db_client.DbClient, error) {
+	connString, err := getLocalSteampipeConnectionString(nil)
+	if err != nil {
+		return nil, err
+	}
+	return db_client.NewDbClient(ctx, connString, nil, opts...)
+}
+
 // newLocalClient verifies that the local database instance is running and returns a LocalDbClient to interact with it
 // (This FAILS if local service is not running - use GetLocalClient to start service first)
 func newLocalClient(ctx context.Context, invoker constants.Invoker, onConnectionCallback db_client.DbConnectionCallback, opts ...db_client.ClientOption) (*LocalDbClient, error) {
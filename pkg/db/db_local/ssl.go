@@ -24,6 +24,9 @@ import (
 const (
 	CertIssuer               = "steampipe.io"
 	ServerCertValidityPeriod = 3 * (365 * (24 * time.Hour)) // 3 years
+	// the dashboard server certificate is verified by browsers - Apple platforms reject server certificates
+	// which are valid for more than 825 days, and other browsers limit public certificates to 398 days
+	DashboardServerCertValidityPeriod = 397 * (24 * time.Hour)
 )
 
 var EndOfTime = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
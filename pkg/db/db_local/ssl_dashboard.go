@@ -0,0 +1,133 @@
+package db_local
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/db/sslio"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// EnsureDashboardServerCertificate ensures there is a valid dashboard server certificate signed by the
+// steampipe root certificate, generating the root certificate if needed
+// it returns the locations of the certificate and private key
+func EnsureDashboardServerCertificate() (string, string, error) {
+	utils.LogTime("db_local.EnsureDashboardServerCertificate start")
+	defer utils.LogTime("db_local.EnsureDashboardServerCertificate end")
+
+	certLocation := filepaths.DashboardServerCertLocation()
+	keyLocation := filepaths.DashboardServerCertKeyLocation()
+
+	// ensure the root certificate (and the database server certificate signed by it) exist
+	if err := ensureCertificates(); err != nil {
+		return "", "", sperr.WrapWithMessage(err, "failed to create the steampipe root certificate")
+	}
+	rootPrivateKey, err := loadRootPrivateKey()
+	if err != nil {
+		return "", "", sperr.WrapWithMessage(err, "failed to load the steampipe root certificate key")
+	}
+	rootCertificate, err := sslio.ParseCertificateInLocation(filepaths.GetRootCertLocation())
+	if err != nil {
+		return "", "", sperr.WrapWithMessage(err, "failed to load the steampipe root certificate")
+	}
+
+	if isDashboardServerCertificateValid(rootCertificate) {
+		return certLocation, keyLocation, nil
+	}
+	if err := generateDashboardServerCertificate(rootCertificate, rootPrivateKey); err != nil {
+		return "", "", sperr.WrapWithMessage(err, "failed to create the dashboard server certificate")
+	}
+	return certLocation, keyLocation, nil
+}
+
+// isDashboardServerCertificateValid checks the dashboard server certificate and key exist,
+// the certificate is not expiring, its validity period is not too long for browsers,
+// and it was signed by the current root certificate
+func isDashboardServerCertificateValid(rootCertificate *x509.Certificate) bool {
+	if !filehelpers.FileExists(filepaths.DashboardServerCertLocation()) || !filehelpers.FileExists(filepaths.DashboardServerCertKeyLocation()) {
+		return false
+	}
+	certificate, err := sslio.ParseCertificateInLocation(filepaths.DashboardServerCertLocation())
+	if err != nil {
+		return false
+	}
+	if isCerticateExpiring(certificate) {
+		return false
+	}
+	// certificates generated by earlier versions were valid for too long to be accepted by all browsers
+	if certificate.NotAfter.Sub(certificate.NotBefore) > DashboardServerCertValidityPeriod {
+		return false
+	}
+	// if the root certificate has been regenerated, the dashboard certificate must be regenerated too
+	return certificate.CheckSignatureFrom(rootCertificate) == nil
+}
+
+// generateDashboardServerCertificate creates a certificate for the dashboard server signed by the CA certificate
+// unlike the database server certificate, this is verified by browsers so must include the host names and addresses
+func generateDashboardServerCertificate(caCertificateData *x509.Certificate, caPrivateKey *rsa.PrivateKey) error {
+	utils.LogTime("db_local.generateDashboardServerCertificate start")
+	defer utils.LogTime("db_local.generateDashboardServerCertificate end")
+
+	now := time.Now()
+	// browsers reject certificates from the same issuer with duplicate serial numbers, so use a random serial number
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+
+	dnsNames := []string{"localhost"}
+	if hostname, err := os.Hostname(); err == nil {
+		dnsNames = append(dnsNames, hostname)
+	}
+	ipAddresses := []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	if addresses, err := utils.LocalPublicAddresses(); err == nil {
+		for _, a := range addresses {
+			if ip := net.ParseIP(a); ip != nil {
+				ipAddresses = append(ipAddresses, ip)
+			}
+		}
+	}
+
+	certificateData := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: "localhost", Organization: []string{CertIssuer}},
+		Issuer:       caCertificateData.Subject,
+		NotBefore:    now,
+		NotAfter:     now.Add(DashboardServerCertValidityPeriod),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     dnsNames,
+		IPAddresses:  ipAddresses,
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+
+	certBytes, err := x509.CreateCertificate(rand.Reader, certificateData, caCertificateData, &privateKey.PublicKey, caPrivateKey)
+	if err != nil {
+		log.Println("[INFO] Failed to create dashboard server certificate")
+		return err
+	}
+
+	if err := sslio.WriteCertificate(filepaths.DashboardServerCertLocation(), certBytes); err != nil {
+		log.Println("[INFO] Failed to save dashboard server certificate")
+		return err
+	}
+	if err := sslio.WritePrivateKey(filepaths.DashboardServerCertKeyLocation(), privateKey); err != nil {
+		log.Println("[INFO] Failed to save dashboard server private key")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package db_local
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/db/sslio"
+	"github.com/turbot/steampipe/pkg/filepaths"
+)
+
+func TestEnsureDashboardServerCertificate(t *testing.T) {
+	filepaths.SteampipeDir = t.TempDir()
+	filepaths.EnsureDatabaseDir()
+
+	certLocation, _, err := EnsureDashboardServerCertificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the certificate must be trusted by a client which trusts the steampipe root certificate
+	certificate, err := sslio.ParseCertificateInLocation(certLocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootCertificate, err := sslio.ParseCertificateInLocation(filepaths.GetRootCertLocation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCertificate)
+	for _, host := range []string{"localhost", "127.0.0.1"} {
+		if _, err := certificate.Verify(x509.VerifyOptions{Roots: roots, DNSName: host}); err != nil {
+			t.Errorf("certificate is not valid for %s: %v", host, err)
+		}
+	}
+
+	// browsers on Apple platforms reject server certificates which are valid for more than 825 days
+	if validity := certificate.NotAfter.Sub(certificate.NotBefore); validity > 397*24*time.Hour {
+		t.Errorf("expected the certificate to be valid for at most 397 days, got %s (NotAfter %s)", validity, certificate.NotAfter)
+	}
+
+	// a valid certificate must not be regenerated
+	before, _ := os.Stat(certLocation)
+	if _, _, err := EnsureDashboardServerCertificate(); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.Stat(certLocation)
+	if !before.ModTime().Equal(after.ModTime()) {
+		t.Errorf("expected the existing certificate to be reused")
+	}
+}
+
+func TestEnsureDashboardServerCertificateReplacesLongValidity(t *testing.T) {
+	filepaths.SteampipeDir = t.TempDir()
+	filepaths.EnsureDatabaseDir()
+	if _, _, err := EnsureDashboardServerCertificate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// replace the certificate with one valid for 3 years, as generated by earlier versions
+	rootCertificate, err := sslio.ParseCertificateInLocation(filepaths.GetRootCertLocation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootPrivateKey, err := loadRootPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	certificateData := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    now,
+		NotAfter:     now.Add(ServerCertValidityPeriod),
+		DNSNames:     []string{"localhost"},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, certificateData, rootCertificate, &privateKey.PublicKey, rootPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sslio.WriteCertificate(filepaths.DashboardServerCertLocation(), certBytes); err != nil {
+		t.Fatal(err)
+	}
+
+	certLocation, _, err := EnsureDashboardServerCertificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := sslio.ParseCertificateInLocation(certLocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validity := certificate.NotAfter.Sub(certificate.NotBefore); validity > DashboardServerCertValidityPeriod {
+		t.Errorf("expected the certificate to be regenerated, got validity %s", validity)
+	}
+}
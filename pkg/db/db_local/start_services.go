@@ -150,7 +150,7 @@ func ensurePluginManager() (*pluginmanager.PluginManagerClient, *pluginmanager.S
 			log.Printf("[WARN] plugin manager start() - failed to get steampipe executable path: %s", err)
 			return nil, nil, err
 		}
-		if state, err = pluginmanager.StartNewInstance(executable); err != nil {
+		if state, err = pluginmanager.StartNewInstance(executable, viper.GetString(constants.ArgMetricsListen)); err != nil {
 			log.Printf("[WARN] StartServices plugin manager failed to start: %s", err)
 			return nil, nil, err
 		}
@@ -185,6 +185,9 @@ func postServiceStart(ctx context.Context, res *StartResult) error {
 	if err := PopulatePluginTable(ctx, conn); err != nil {
 		return err
 	}
+	if err := initializeCheckResultTables(ctx, conn); err != nil {
+		return err
+	}
 
 	statushooks.SetStatus(ctx, "Create steampipe_server_settings table")
 	// create the server settings table
@@ -99,3 +99,12 @@ func ParseJSONOutputColumnValue(val interface{}, col *queryresult.ColumnDef) (in
 		return ColumnValueAsString(val, col)
 	}
 }
+
+// isNumericColumn returns whether the column holds numbers (which should be right aligned in tabular output)
+func isNumericColumn(col *queryresult.ColumnDef) bool {
+	switch col.DataType {
+	case "INT2", "INT4", "INT8", "FLOAT4", "FLOAT8", "NUMERIC":
+		return true
+	}
+	return false
+}
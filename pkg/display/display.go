@@ -7,6 +7,7 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -38,16 +39,34 @@ func ShowOutput(ctx context.Context, result *queryresult.Result, opts ...Display
 	switch cmdconfig.Viper().GetString(constants.ArgOutput) {
 	case constants.OutputFormatJSON:
 		rowErrors = displayJSON(ctx, result)
+	case constants.OutputFormatJSONL:
+		rowErrors = displayJSONL(ctx, result)
 	case constants.OutputFormatCSV:
 		rowErrors = displayCSV(ctx, result)
+	case constants.OutputFormatMarkdown:
+		rowErrors = displayMarkdown(ctx, result)
+	case constants.OutputFormatHTML:
+		rowErrors = displayHTML(ctx, result)
+	case constants.OutputFormatYAML:
+		rowErrors = displayYAML(ctx, result)
 	case constants.OutputFormatLine:
 		rowErrors = displayLine(ctx, result)
 	case constants.OutputFormatTable:
 		rowErrors = displayTable(ctx, result)
 	}
 
-	if config.timing {
-		fmt.Println(buildTimingString(result))
+	if result.Truncated {
+		error_helpers.ShowWarning(fmt.Sprintf("query returned more than %d rows - the result has been truncated (see --max-rows)", cmdconfig.Viper().GetInt(constants.ArgMaxRows)))
+	}
+
+	if config.timing || config.explain {
+		timingResult := <-result.TimingResult
+		if config.explain {
+			fmt.Println(buildExplainString(timingResult))
+		}
+		if config.timing {
+			fmt.Println(buildTimingString(timingResult))
+		}
 	}
 	// return the number of rows that returned errors
 	return rowErrors
@@ -85,6 +104,43 @@ func ShowWrappedTable(headers []string, rows [][]string, opts *ShowWrappedTableO
 	t.Render()
 }
 
+// ShowHighlightedTable displays the rows in a table, highlighting the rows for which highlight is true
+func ShowHighlightedTable(headers []string, rows [][]string, highlight []bool) {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleDefault)
+	t.Style().Format.Header = text.FormatDefault
+	t.SetOutputMirror(os.Stdout)
+
+	colConfigs := make([]table.ColumnConfig, len(headers))
+	headerRow := make(table.Row, len(headers))
+	for idx, header := range headers {
+		headerRow[idx] = header
+		colConfigs[idx] = table.ColumnConfig{
+			Name:     header,
+			Number:   idx + 1,
+			WidthMax: constants.MaxColumnWidth,
+		}
+	}
+	t.SetColumnConfigs(colConfigs)
+	t.AppendHeader(headerRow)
+
+	for _, row := range rows {
+		rowObj := table.Row{}
+		for _, col := range row {
+			rowObj = append(rowObj, col)
+		}
+		t.AppendRow(rowObj)
+	}
+	t.SetRowPainter(table.RowPainterWithAttributes(func(_ table.Row, attr table.RowAttributes) text.Colors {
+		// row numbers are 1-based
+		if idx := attr.Number - 1; idx < len(highlight) && highlight[idx] {
+			return text.Colors{text.FgYellow}
+		}
+		return nil
+	}))
+	t.Render()
+}
+
 func GetMaxCols() int {
 	colsAvailable, _, _ := gows.GetWinSize()
 	// check if STEAMPIPE_DISPLAY_WIDTH env variable is set
@@ -236,12 +292,7 @@ func displayJSON(ctx context.Context, result *queryresult.Result) int {
 
 	// define function to add each row to the JSON output
 	rowFunc := func(row []interface{}, result *queryresult.Result) {
-		record := map[string]interface{}{}
-		for idx, col := range result.Cols {
-			value, _ := ParseJSONOutputColumnValue(row[idx], col)
-			record[col.Name] = value
-		}
-		jsonOutput = append(jsonOutput, record)
+		jsonOutput = append(jsonOutput, rowToJSONRecord(row, result.Cols))
 	}
 
 	// call this function for each row
@@ -261,6 +312,50 @@ func displayJSON(ctx context.Context, result *queryresult.Result) int {
 	return rowErrors
 }
 
+// displayJSONL writes each row as a JSON object on its own line as soon as it is read
+// unlike displayJSON, this does not hold the result in memory, so is suitable for very large results
+func displayJSONL(ctx context.Context, result *queryresult.Result) int {
+	return displayWithWriter(ctx, result, writeJSONL)
+}
+
+func displayMarkdown(ctx context.Context, result *queryresult.Result) int {
+	showHeader := cmdconfig.Viper().GetBool(constants.ArgHeader)
+	return displayWithWriter(ctx, result, func(w io.Writer, result *queryresult.Result) error {
+		return writeMarkdownTable(w, result, showHeader)
+	})
+}
+
+func displayHTML(ctx context.Context, result *queryresult.Result) int {
+	showHeader := cmdconfig.Viper().GetBool(constants.ArgHeader)
+	return displayWithWriter(ctx, result, func(w io.Writer, result *queryresult.Result) error {
+		return writeHTMLTable(w, result, showHeader)
+	})
+}
+
+func displayYAML(ctx context.Context, result *queryresult.Result) int {
+	return displayWithWriter(ctx, result, writeYAML)
+}
+
+// displayWithWriter writes the result to stdout using the given query result writer
+func displayWithWriter(ctx context.Context, result *queryresult.Result, writer queryResultWriterFunc) int {
+	rowErrors := 0
+	if err := writer(os.Stdout, result); err != nil {
+		error_helpers.ShowError(ctx, err)
+		rowErrors++
+	}
+	return rowErrors
+}
+
+// rowToJSONRecord converts a row into a map of column name to value
+func rowToJSONRecord(row []interface{}, cols []*queryresult.ColumnDef) map[string]interface{} {
+	record := map[string]interface{}{}
+	for idx, col := range cols {
+		value, _ := ParseJSONOutputColumnValue(row[idx], col)
+		record[col.Name] = value
+	}
+	return record
+}
+
 func displayCSV(ctx context.Context, result *queryresult.Result) int {
 	rowErrors := 0
 	csvWriter := csv.NewWriter(os.Stdout)
@@ -355,8 +450,7 @@ func displayTable(ctx context.Context, result *queryresult.Result) int {
 	return rowErrors
 }
 
-func buildTimingString(result *queryresult.Result) string {
-	timingResult := <-result.TimingResult
+func buildTimingString(timingResult *queryresult.TimingResult) string {
 	if timingResult == nil {
 		return ""
 	}
@@ -414,7 +508,11 @@ func iterateResults(result *queryresult.Result, displayResult displayResultsFunc
 
 // DisplayErrorTiming shows the time taken for the query to fail
 func DisplayErrorTiming(t time.Time) {
-	elapsed := time.Since(t)
+	DisplayErrorDuration(time.Since(t))
+}
+
+// DisplayErrorDuration shows the time a failed query ran for
+func DisplayErrorDuration(elapsed time.Duration) {
 	var sb strings.Builder
 	// large numbers should be formatted with commas
 	p := message.NewPrinter(language.English)
@@ -6,28 +6,33 @@ import (
 )
 
 type displayConfiguration struct {
-	timing bool
+	timing  bool
+	explain bool
 }
 
-// NewDisplayConfiguration creates a default configuration with timing set to
-// true if both --timing is true and --output is table
+// NewDisplayConfiguration creates a default configuration with timing (and explain) set to
+// true if both --timing (--explain) is true and --output is table
 func NewDisplayConfiguration() *displayConfiguration {
 	timingFlag := cmdconfig.Viper().GetBool(constants.ArgTiming)
+	explainFlag := cmdconfig.Viper().GetBool(constants.ArgExplain)
 	isInteractive := cmdconfig.Viper().GetBool(constants.ConfigKeyInteractive)
 	outputTable := cmdconfig.Viper().GetString(constants.ArgOutput) == constants.OutputFormatTable
 
 	timing := timingFlag && (outputTable || isInteractive)
+	explain := explainFlag && (outputTable || isInteractive)
 
 	return &displayConfiguration{
-		timing: timing,
+		timing:  timing,
+		explain: explain,
 	}
 }
 
 type DisplayOption = func(config *displayConfiguration)
 
-// WithTimingDisabled forcefully disables display of timing data
+// WithTimingDisabled forcefully disables display of timing data (including the explain output)
 func WithTimingDisabled() DisplayOption {
 	return func(o *displayConfiguration) {
 		o.timing = false
+		o.explain = false
 	}
 }
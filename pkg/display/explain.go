@@ -0,0 +1,83 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// buildExplainString builds the display of the query plan, followed by a tree of the foreign table scans made by the query
+func buildExplainString(timingResult *queryresult.TimingResult) string {
+	if timingResult == nil || timingResult.Explain == nil {
+		return ""
+	}
+	explain := timingResult.Explain
+	// large numbers should be formatted with commas
+	p := message.NewPrinter(language.English)
+
+	var sb strings.Builder
+	if len(explain.Plan) > 0 {
+		sb.WriteString(fmt.Sprintf("\n%s\n", constants.Bold("Query plan:")))
+		sb.WriteString(strings.Join(explain.Plan, "\n"))
+		sb.WriteString("\n")
+	}
+
+	if len(explain.Scans) == 0 {
+		sb.WriteString("\nNo foreign table scans.")
+		return sb.String()
+	}
+
+	// scan start times are shown relative to the first scan to start
+	var firstStart time.Time
+	for _, scan := range explain.Scans {
+		if !scan.StartTime.IsZero() && (firstStart.IsZero() || scan.StartTime.Before(firstStart)) {
+			firstStart = scan.StartTime
+		}
+	}
+
+	var totalHydrateCalls int64
+	l := list.NewWriter()
+	l.SetStyle(list.StyleConnectedLight)
+	for _, scan := range explain.Scans {
+		totalHydrateCalls += scan.HydrateCalls
+
+		l.AppendItem(scan.Table)
+		l.Indent()
+		if scan.Connection != "" {
+			l.AppendItem(fmt.Sprintf("connection: %s", scan.Connection))
+		}
+		quals := scan.Quals
+		if quals == "" {
+			quals = "none"
+		}
+		l.AppendItem(fmt.Sprintf("quals: %s", quals))
+		if len(scan.Columns) > 0 {
+			l.AppendItem(fmt.Sprintf("columns: %s", strings.Join(scan.Columns, ", ")))
+		}
+		if scan.Limit != nil {
+			l.AppendItem(p.Sprintf("limit: %d", *scan.Limit))
+		}
+		rowsFetched := p.Sprintf("rows fetched: %d", scan.RowsFetched)
+		if scan.CacheHit {
+			rowsFetched += " (cached)"
+		}
+		l.AppendItem(rowsFetched)
+		l.AppendItem(p.Sprintf("hydrate calls: %d", scan.HydrateCalls))
+		if !scan.StartTime.IsZero() {
+			l.AppendItem(fmt.Sprintf("start: +%s", scan.StartTime.Sub(firstStart).Round(time.Millisecond)))
+		}
+		l.AppendItem(fmt.Sprintf("time: %s", scan.Duration.Round(time.Millisecond)))
+		l.UnIndent()
+	}
+
+	sb.WriteString(fmt.Sprintf("\n%s\n", constants.Bold("Foreign table scans:")))
+	sb.WriteString(l.Render())
+	sb.WriteString(p.Sprintf("\n\nScans: %d. Hydrate calls: %d.", len(explain.Scans), totalHydrateCalls))
+	return sb.String()
+}
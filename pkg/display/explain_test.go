@@ -0,0 +1,95 @@
+package display
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+type buildExplainStringTest struct {
+	explain  *queryresult.ExplainResult
+	expected string
+}
+
+func testCasesBuildExplainString() map[string]buildExplainStringTest {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limit := int64(5)
+	queryPlan := fmt.Sprintf("\n%s\n", constants.Bold("Query plan:"))
+	scansHeader := fmt.Sprintf("\n%s\n", constants.Bold("Foreign table scans:"))
+
+	return map[string]buildExplainStringTest{
+		"no explain": {
+			explain:  nil,
+			expected: "",
+		},
+		"no scans": {
+			explain: &queryresult.ExplainResult{
+				Plan: []string{"Result  (cost=0.00..0.01 rows=1 width=4)"},
+			},
+			expected: queryPlan + "Result  (cost=0.00..0.01 rows=1 width=4)\n" +
+				"\nNo foreign table scans.",
+		},
+		"scans": {
+			explain: &queryresult.ExplainResult{
+				Plan: []string{
+					"Nested Loop  (cost=0.00..2.00 rows=1 width=64)",
+					"  ->  Foreign Scan on aws_s3_bucket  (cost=0.00..1.00 rows=1 width=32)",
+				},
+				Scans: []*queryresult.ScanMetadata{
+					{
+						Connection:   "aws",
+						Table:        "aws_s3_bucket",
+						Quals:        "region = 'us-east-1'",
+						Columns:      []string{"name", "arn"},
+						Limit:        &limit,
+						RowsFetched:  1234,
+						HydrateCalls: 2468,
+						StartTime:    start,
+						Duration:     1500 * time.Millisecond,
+					},
+					{
+						Table:       "aws_account",
+						RowsFetched: 1,
+						CacheHit:    true,
+						StartTime:   start.Add(250 * time.Millisecond),
+						Duration:    1200 * time.Microsecond,
+					},
+				},
+			},
+			expected: queryPlan +
+				"Nested Loop  (cost=0.00..2.00 rows=1 width=64)\n" +
+				"  ->  Foreign Scan on aws_s3_bucket  (cost=0.00..1.00 rows=1 width=32)\n" +
+				scansHeader +
+				"┌─ aws_s3_bucket\n" +
+				"│  ├─ connection: aws\n" +
+				"│  ├─ quals: region = 'us-east-1'\n" +
+				"│  ├─ columns: name, arn\n" +
+				"│  ├─ limit: 5\n" +
+				"│  ├─ rows fetched: 1,234\n" +
+				"│  ├─ hydrate calls: 2,468\n" +
+				"│  ├─ start: +0s\n" +
+				"│  └─ time: 1.5s\n" +
+				"└─ aws_account\n" +
+				"   ├─ quals: none\n" +
+				"   ├─ rows fetched: 1 (cached)\n" +
+				"   ├─ hydrate calls: 0\n" +
+				"   ├─ start: +250ms\n" +
+				"   └─ time: 1ms\n" +
+				"\nScans: 2. Hydrate calls: 2,468.",
+		},
+	}
+}
+
+func TestBuildExplainString(t *testing.T) {
+	for name, test := range testCasesBuildExplainString() {
+		t.Run(name, func(t *testing.T) {
+			timingResult := &queryresult.TimingResult{Explain: test.explain}
+			if got := buildExplainString(timingResult); got != test.expected {
+				t.Errorf("buildExplainString() got:\n%s\nexpected:\n%s", got, test.expected)
+			}
+		})
+	}
+}
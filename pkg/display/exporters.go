@@ -0,0 +1,277 @@
+package display
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	typeHelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/export"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"sigs.k8s.io/yaml/goyaml.v3"
+)
+
+type queryResultWriterFunc func(w io.Writer, result *queryresult.Result) error
+
+// QueryResultExporter is an exporter which writes the rows of a query result to file
+type QueryResultExporter struct {
+	export.ExporterBase
+	name      string
+	extension string
+	writer    queryResultWriterFunc
+}
+
+func newQueryResultExporter(name, extension string, writer queryResultWriterFunc) *QueryResultExporter {
+	return &QueryResultExporter{
+		name:      name,
+		extension: extension,
+		writer:    writer,
+	}
+}
+
+// QueryResultExporters returns an exporter for each of the supported query result export formats
+func QueryResultExporters() []export.Exporter {
+	return []export.Exporter{
+		newQueryResultExporter(constants.OutputFormatCSV, constants.CsvExtension, writeCSV),
+		newQueryResultExporter(constants.OutputFormatJSON, constants.JsonExtension, writeJSON),
+		newQueryResultExporter(constants.OutputFormatJSONL, constants.JsonlExtension, writeJSONL),
+		newQueryResultExporter(constants.OutputFormatMarkdown, constants.MarkdownExtension, writeMarkdown),
+		newQueryResultExporter(constants.OutputFormatHTML, constants.HtmlExtension, writeHTML),
+		newQueryResultExporter(constants.OutputFormatYAML, constants.YamlExtension, writeYAML),
+	}
+}
+
+func (e *QueryResultExporter) Export(_ context.Context, input export.ExportSourceData, filePath string) error {
+	// input must be a query result
+	result, ok := input.(*queryresult.Result)
+	if !ok {
+		return fmt.Errorf("QueryResultExporter input must be *queryresult.Result")
+	}
+
+	// stream the rows straight to file as they are read, rather than building the output in memory
+	err := export.WriteStream(filePath, func(w io.Writer) error {
+		return e.writer(w, result)
+	})
+	if err != nil {
+		// read any remaining rows, so the query is not blocked by a failed export
+		result.Drain()
+	}
+	return err
+}
+
+func (e *QueryResultExporter) FileExtension() string {
+	return e.extension
+}
+
+func (e *QueryResultExporter) Name() string {
+	return e.name
+}
+
+func writeCSV(w io.Writer, result *queryresult.Result) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(ColumnNames(result.Cols)); err != nil {
+		return err
+	}
+
+	rowFunc := func(row []interface{}, result *queryresult.Result) {
+		rowAsString, _ := ColumnValuesAsString(row, result.Cols, WithNullString(""))
+		_ = csvWriter.Write(rowAsString)
+	}
+	err := iterateResults(result, rowFunc)
+
+	csvWriter.Flush()
+	if err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
+
+func writeJSON(w io.Writer, result *queryresult.Result) error {
+	jsonOutput := make([]map[string]interface{}, 0)
+
+	rowFunc := func(row []interface{}, result *queryresult.Result) {
+		jsonOutput = append(jsonOutput, rowToJSONRecord(row, result.Cols))
+	}
+	if err := iterateResults(result, rowFunc); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", " ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(jsonOutput)
+}
+
+func writeJSONL(w io.Writer, result *queryresult.Result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	var encodeErr error
+	rowFunc := func(row []interface{}, result *queryresult.Result) {
+		// once an encode has failed, just drain the remaining rows
+		if encodeErr == nil {
+			encodeErr = encoder.Encode(rowToJSONRecord(row, result.Cols))
+		}
+	}
+	if err := iterateResults(result, rowFunc); err != nil {
+		return err
+	}
+	return encodeErr
+}
+
+func writeMarkdown(w io.Writer, result *queryresult.Result) error {
+	return writeMarkdownTable(w, result, true)
+}
+
+// writeMarkdownTable writes the result as a markdown table, right aligning numeric columns
+// a markdown table must have a header row, so if showHeader is false the header cells are left empty
+func writeMarkdownTable(w io.Writer, result *queryresult.Result, showHeader bool) error {
+	colNames := ColumnNames(result.Cols)
+	separators := make([]string, len(colNames))
+	for i, name := range colNames {
+		colNames[i] = ""
+		if showHeader {
+			colNames[i] = escapeMarkdownCell(name)
+		}
+		separators[i] = "---"
+		if isNumericColumn(result.Cols[i]) {
+			separators[i] = "---:"
+		}
+	}
+	writeErr := writeMarkdownRow(w, colNames)
+	if writeErr == nil {
+		writeErr = writeMarkdownRow(w, separators)
+	}
+
+	rowFunc := func(row []interface{}, result *queryresult.Result) {
+		// once a write has failed, just drain the remaining rows
+		if writeErr != nil {
+			return
+		}
+		rowAsString, _ := ColumnValuesAsString(row, result.Cols, WithNullString(""))
+		for i, val := range rowAsString {
+			rowAsString[i] = escapeMarkdownCell(val)
+		}
+		writeErr = writeMarkdownRow(w, rowAsString)
+	}
+	if err := iterateResults(result, rowFunc); err != nil {
+		return err
+	}
+	return writeErr
+}
+
+func writeMarkdownRow(w io.Writer, cells []string) error {
+	_, err := io.WriteString(w, "| "+strings.Join(cells, " | ")+" |\n")
+	return err
+}
+
+// escapeMarkdownCell escapes characters which would break the layout of a markdown table
+func escapeMarkdownCell(val string) string {
+	val = strings.ReplaceAll(val, "|", `\|`)
+	val = strings.ReplaceAll(val, "\r\n", "<br>")
+	return strings.ReplaceAll(val, "\n", "<br>")
+}
+
+func writeHTML(w io.Writer, result *queryresult.Result) error {
+	return writeHTMLTable(w, result, true)
+}
+
+// writeHTMLTable writes the result as an html table
+// all styling is inline so the table renders the same wherever it is pasted
+func writeHTMLTable(w io.Writer, result *queryresult.Result, showHeader bool) error {
+	var sb strings.Builder
+	sb.WriteString("<table style=\"border-collapse: collapse; font-family: monospace;\">\n")
+	if showHeader {
+		sb.WriteString("  <thead>\n    <tr>\n")
+		for _, col := range result.Cols {
+			sb.WriteString(fmt.Sprintf("      <th style=\"%s\">%s</th>\n", htmlCellStyle(col), html.EscapeString(col.Name)))
+		}
+		sb.WriteString("    </tr>\n  </thead>\n")
+	}
+	sb.WriteString("  <tbody>\n")
+	_, writeErr := io.WriteString(w, sb.String())
+
+	// write each row as it is read, rather than building the table in memory
+	rowFunc := func(row []interface{}, result *queryresult.Result) {
+		// once a write has failed, just drain the remaining rows
+		if writeErr != nil {
+			return
+		}
+		rowAsString, _ := ColumnValuesAsString(row, result.Cols, WithNullString(""))
+		var rowBuilder strings.Builder
+		rowBuilder.WriteString("    <tr>\n")
+		for i, val := range rowAsString {
+			rowBuilder.WriteString(fmt.Sprintf("      <td style=\"%s\">%s</td>\n", htmlCellStyle(result.Cols[i]), html.EscapeString(val)))
+		}
+		rowBuilder.WriteString("    </tr>\n")
+		_, writeErr = io.WriteString(w, rowBuilder.String())
+	}
+	if err := iterateResults(result, rowFunc); err != nil {
+		return err
+	}
+	if writeErr != nil {
+		return writeErr
+	}
+	_, err := io.WriteString(w, "  </tbody>\n</table>\n")
+	return err
+}
+
+func htmlCellStyle(col *queryresult.ColumnDef) string {
+	align := "left"
+	if isNumericColumn(col) {
+		align = "right"
+	}
+	return fmt.Sprintf("border: 1px solid #ccc; padding: 4px 8px; vertical-align: top; white-space: pre-wrap; text-align: %s;", align)
+}
+
+// writeYAML writes the result as a yaml sequence of records, keeping the keys of each record in column order
+func writeYAML(w io.Writer, result *queryresult.Result) error {
+	rowCount := 0
+	var writeErr error
+	rowFunc := func(row []interface{}, result *queryresult.Result) {
+		// once a write has failed, just drain the remaining rows
+		if writeErr != nil {
+			return
+		}
+		rowCount++
+		// marshal each row as a single item sequence - these concatenate to give a sequence of all rows,
+		// without needing to hold the full result in memory
+		var rowYAML []byte
+		rowYAML, writeErr = yaml.Marshal(&yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Content: []*yaml.Node{rowToYAMLNode(row, result.Cols)},
+		})
+		if writeErr == nil {
+			_, writeErr = w.Write(rowYAML)
+		}
+	}
+	if err := iterateResults(result, rowFunc); err != nil {
+		return err
+	}
+	if writeErr == nil && rowCount == 0 {
+		_, writeErr = io.WriteString(w, "[]\n")
+	}
+	return writeErr
+}
+
+// rowToYAMLNode converts a row into a yaml mapping node of column name to value
+func rowToYAMLNode(row []interface{}, cols []*queryresult.ColumnDef) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for idx, col := range cols {
+		value, _ := ParseJSONOutputColumnValue(row[idx], col)
+		keyNode, valueNode := &yaml.Node{}, &yaml.Node{}
+		// encode the key as well as the value so that names such as 'true' are quoted
+		_ = keyNode.Encode(col.Name)
+		if err := valueNode.Encode(value); err != nil {
+			// fall back to the string representation of the value
+			_ = valueNode.Encode(typeHelpers.ToString(value))
+		}
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+	return node
+}
@@ -0,0 +1,163 @@
+package display
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+type queryResultWriterTest struct {
+	writer   queryResultWriterFunc
+	expected string
+}
+
+func testCasesQueryResultWriter() map[string]queryResultWriterTest {
+	return map[string]queryResultWriterTest{
+		"csv": {
+			writer:   writeCSV,
+			expected: "name,count\na,1\n\"b,c\",\n",
+		},
+		"jsonl": {
+			writer:   writeJSONL,
+			expected: "{\"count\":1,\"name\":\"a\"}\n{\"count\":null,\"name\":\"b,c\"}\n",
+		},
+		"markdown": {
+			writer:   writeMarkdown,
+			expected: "| name | count |\n| --- | ---: |\n| a | 1 |\n| b,c |  |\n",
+		},
+		"html": {
+			writer: writeHTML,
+			expected: `<table style="border-collapse: collapse; font-family: monospace;">
+  <thead>
+    <tr>
+      <th style="` + htmlTestLeftStyle + `">name</th>
+      <th style="` + htmlTestRightStyle + `">count</th>
+    </tr>
+  </thead>
+  <tbody>
+    <tr>
+      <td style="` + htmlTestLeftStyle + `">a</td>
+      <td style="` + htmlTestRightStyle + `">1</td>
+    </tr>
+    <tr>
+      <td style="` + htmlTestLeftStyle + `">b,c</td>
+      <td style="` + htmlTestRightStyle + `"></td>
+    </tr>
+  </tbody>
+</table>
+`,
+		},
+		"yaml": {
+			writer:   writeYAML,
+			expected: "- name: a\n  count: 1\n- name: b,c\n  count: null\n",
+		},
+	}
+}
+
+const (
+	htmlTestLeftStyle  = "border: 1px solid #ccc; padding: 4px 8px; vertical-align: top; white-space: pre-wrap; text-align: left;"
+	htmlTestRightStyle = "border: 1px solid #ccc; padding: 4px 8px; vertical-align: top; white-space: pre-wrap; text-align: right;"
+)
+
+func testQueryResult() *queryresult.BufferedResult {
+	return &queryresult.BufferedResult{
+		Cols: []*queryresult.ColumnDef{
+			{Name: "name", DataType: "TEXT"},
+			{Name: "count", DataType: "INT8"},
+		},
+		Rows: []*queryresult.RowResult{
+			{Data: []interface{}{"a", int64(1)}},
+			{Data: []interface{}{"b,c", nil}},
+		},
+	}
+}
+
+func TestQueryResultWriters(t *testing.T) {
+	for name, test := range testCasesQueryResultWriter() {
+		var buf bytes.Buffer
+		if err := test.writer(&buf, testQueryResult().Replay()); err != nil {
+			t.Errorf("Test: '%s'' FAILED : unexpected error %v", name, err)
+			continue
+		}
+		if output := buf.String(); output != test.expected {
+			t.Errorf("Test: '%s'' FAILED : \nexpected:\n %v \ngot:\n %v\n", name, test.expected, output)
+		}
+	}
+}
+
+func TestWriteHTMLTableEscaping(t *testing.T) {
+	result := &queryresult.BufferedResult{
+		Cols: []*queryresult.ColumnDef{{Name: "<b>name</b>", DataType: "TEXT"}},
+		Rows: []*queryresult.RowResult{
+			{Data: []interface{}{`<script>alert("x & y")</script>`}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeHTMLTable(&buf, result.Replay(), false); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<table style="border-collapse: collapse; font-family: monospace;">
+  <tbody>
+    <tr>
+      <td style="` + htmlTestLeftStyle + `">&lt;script&gt;alert(&#34;x &amp; y&#34;)&lt;/script&gt;</td>
+    </tr>
+  </tbody>
+</table>
+`
+	if output := buf.String(); output != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, output)
+	}
+
+	buf.Reset()
+	if err := writeHTMLTable(&buf, result.Replay(), true); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `<th style="` + htmlTestLeftStyle + `">&lt;b&gt;name&lt;/b&gt;</th>`; !strings.Contains(buf.String(), expected) {
+		t.Errorf("expected the column name to be escaped, got:\n%s", buf.String())
+	}
+}
+
+func TestQueryResultExporter(t *testing.T) {
+	exporter := newQueryResultExporter("csv", ".csv", writeCSV)
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "result.csv")
+
+	if err := exporter.Export(context.Background(), testQueryResult().Replay(), filePath); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filePath); string(data) != testCasesQueryResultWriter()["csv"].expected {
+		t.Errorf("unexpected export:\n%s", data)
+	}
+
+	// if the result fails part way through, the error is returned and the existing file is left in place
+	failingResult := testQueryResult()
+	failingResult.Rows = append(failingResult.Rows, &queryresult.RowResult{Error: errors.New("query failed")})
+	if err := exporter.Export(context.Background(), failingResult.Replay(), filePath); err == nil || err.Error() != "query failed" {
+		t.Errorf("expected 'query failed' error, got %v", err)
+	}
+	if data, _ := os.ReadFile(filePath); string(data) != testCasesQueryResultWriter()["csv"].expected {
+		t.Errorf("expected the previous export to be unchanged, got:\n%s", data)
+	}
+	// no partial or temporary files are left behind
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected only the previous export in the directory, got %d files", len(entries))
+	}
+
+	// a failed export to a new file does not create the file
+	newFilePath := filepath.Join(dir, "new.csv")
+	failingResult = testQueryResult()
+	failingResult.Rows = append(failingResult.Rows, &queryresult.RowResult{Error: errors.New("query failed")})
+	if err := exporter.Export(context.Background(), failingResult.Replay(), newFilePath); err == nil {
+		t.Errorf("expected an error")
+	}
+	if _, err := os.Stat(newFilePath); !os.IsNotExist(err) {
+		t.Errorf("expected no file to be created for a failed export")
+	}
+}
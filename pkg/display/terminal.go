@@ -5,3 +5,8 @@ import "fmt"
 func ClearCurrentLine() {
 	fmt.Print("\n\033[1A\033[K")
 }
+
+// ClearScreen erases the screen and moves the cursor to the top left
+func ClearScreen() {
+	fmt.Print("\033[2J\033[H")
+}
@@ -14,6 +14,14 @@ type Exporter interface {
 	Alias() string
 }
 
+// MultiFileExporter is implemented by exporters which write a separate file for each item of the input
+// (e.g. each panel of a dashboard), into a directory named after the export target
+type MultiFileExporter interface {
+	Exporter
+	// ExportFiles writes the files to the directory (creating it if needed) and returns their names
+	ExportFiles(ctx context.Context, input ExportSourceData, dirPath string) ([]string, error)
+}
+
 type ExporterBase struct{}
 
 func (*ExporterBase) Alias() string {
@@ -1,9 +1,11 @@
 package export
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -14,13 +16,40 @@ func GenerateDefaultExportFileName(executionName, fileExtension string) string {
 }
 
 func Write(filePath string, exportData io.Reader) error {
-	// create the output file
-	destination, err := os.Create(filePath)
+	return WriteStream(filePath, func(w io.Writer) error {
+		_, err := io.Copy(w, exportData)
+		return err
+	})
+}
+
+// WriteStream creates the file using the given write function
+// the data is written to a temporary file which is renamed once complete,
+// so a failed export does not leave a partial file (or overwrite an existing one)
+func WriteStream(filePath string, write func(w io.Writer) error) (err error) {
+	destination, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
 	if err != nil {
 		return err
 	}
-	defer destination.Close()
+	defer func() {
+		if err != nil {
+			os.Remove(destination.Name())
+		}
+	}()
 
-	_, err = io.Copy(destination, exportData)
-	return err
+	w := bufio.NewWriter(destination)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = destination.Chmod(0644)
+	}
+	// the file is not fully written until it is closed
+	if closeErr := destination.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(destination.Name(), filePath)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
@@ -17,6 +18,8 @@ import (
 type Manager struct {
 	registeredExporters  map[string]Exporter
 	registeredExtensions map[string]Exporter
+	// if set, relative export paths are resolved against this directory rather than the working directory
+	exportDir string
 }
 
 func NewManager() *Manager {
@@ -26,6 +29,11 @@ func NewManager() *Manager {
 	}
 }
 
+// SetExportDir sets the directory which relative export paths are resolved against
+func (m *Manager) SetExportDir(exportDir string) {
+	m.exportDir = exportDir
+}
+
 func (m *Manager) Register(exporter Exporter) error {
 	name := exporter.Name()
 	if _, ok := m.registeredExporters[name]; ok {
@@ -117,7 +125,7 @@ func (m *Manager) getExportTarget(export, executionName string) (*Target, error)
 	if e, ok := m.registeredExporters[export]; ok {
 		t := &Target{
 			exporter: e,
-			filePath: GenerateDefaultExportFileName(executionName, e.FileExtension()),
+			filePath: m.resolveExportPath(GenerateDefaultExportFileName(executionName, e.FileExtension())),
 		}
 		return t, nil
 	}
@@ -127,7 +135,7 @@ func (m *Manager) getExportTarget(export, executionName string) (*Target, error)
 	if e, ok := m.registeredExtensions[ext]; ok {
 		t := &Target{
 			exporter:      e,
-			filePath:      export,
+			filePath:      m.resolveExportPath(export),
 			isNamedTarget: true,
 		}
 		return t, nil
@@ -136,6 +144,13 @@ func (m *Manager) getExportTarget(export, executionName string) (*Target, error)
 	return nil, fmt.Errorf("formatter satisfying '%s' not found", export)
 }
 
+func (m *Manager) resolveExportPath(filePath string) string {
+	if m.exportDir == "" || filepath.IsAbs(filePath) {
+		return filePath
+	}
+	return filepath.Join(m.exportDir, filePath)
+}
+
 func (m *Manager) DoExport(ctx context.Context, targetName string, source ExportSourceData, exports []string) ([]string, error) {
 	var errors []error
 	var msg string
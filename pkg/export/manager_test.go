@@ -2,6 +2,7 @@ package export
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/turbot/steampipe/pkg/constants"
@@ -132,3 +133,33 @@ func TestDoExport(t *testing.T) {
 		}
 	}
 }
+
+func TestExportDir(t *testing.T) {
+	m := NewManager()
+	m.Register(&dummyCSVExporter)
+	m.SetExportDir("/mods/cis")
+
+	tests := map[string]string{
+		// relative paths are resolved against the export dir
+		"output/file.csv": "/mods/cis/output/file.csv",
+		"/tmp/file.csv":   "/tmp/file.csv",
+	}
+	for input, expected := range tests {
+		targets, err := m.resolveTargetsFromArgs([]string{input}, "dummy_execution_name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if targets[0].filePath != expected {
+			t.Errorf("%s: expected file path %s, got %s", input, expected, targets[0].filePath)
+		}
+	}
+
+	// the default file name of a format is also written to the export dir
+	targets, err := m.resolveTargetsFromArgs([]string{"csv"}, "dummy_execution_name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(targets[0].filePath, "/mods/cis/dummy_execution_name.") {
+		t.Errorf("csv: expected the file to be in the export dir, got %s", targets[0].filePath)
+	}
+}
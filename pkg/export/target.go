@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
+
+	"github.com/turbot/steampipe/pkg/utils"
 )
 
 type Target struct {
@@ -13,6 +16,10 @@ type Target struct {
 }
 
 func (t *Target) Export(ctx context.Context, input ExportSourceData) (string, error) {
+	if multiFileExporter, ok := t.exporter.(MultiFileExporter); ok {
+		return t.exportFiles(ctx, multiFileExporter, input)
+	}
+
 	err := t.exporter.Export(ctx, input, t.filePath)
 	if err != nil {
 		return "", err
@@ -21,3 +28,23 @@ func (t *Target) Export(ctx context.Context, input ExportSourceData) (string, er
 		return fmt.Sprintf("File exported to %s/%s", pwd, t.filePath), nil
 	}
 }
+
+// exportFiles exports to a directory with the target file path, minus the file extension
+// it returns a message listing the exported files
+func (t *Target) exportFiles(ctx context.Context, exporter MultiFileExporter, input ExportSourceData) (string, error) {
+	dirPath := strings.TrimSuffix(t.filePath, exporter.FileExtension())
+	files, err := exporter.ExportFiles(ctx, input, dirPath)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return fmt.Sprintf("No %s files exported - there is no data to export", exporter.Name()), nil
+	}
+
+	pwd, _ := os.Getwd()
+	lines := []string{fmt.Sprintf("%d %s exported to %s/%s:", len(files), utils.Pluralize("file", len(files)), pwd, dirPath)}
+	for _, f := range files {
+		lines = append(lines, fmt.Sprintf("  %s", f))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type testMultiFileExporter struct {
+	testExporter
+	files   []string
+	dirPath string
+}
+
+func (t *testMultiFileExporter) ExportFiles(_ context.Context, _ ExportSourceData, dirPath string) ([]string, error) {
+	t.dirPath = dirPath
+	return t.files, nil
+}
+
+func TestTargetExportFiles(t *testing.T) {
+	exporter := &testMultiFileExporter{
+		testExporter: testExporter{extension: ".csv", name: "csv"},
+		files:        []string{"mod.table.t1.csv", "mod.card.c1.csv"},
+	}
+	target := &Target{exporter: exporter, filePath: "mod.dashboard.d1.20230101T000000.csv"}
+
+	msg, err := target.Export(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the files are exported to a directory named after the target, without the extension
+	if expected := "mod.dashboard.d1.20230101T000000"; exporter.dirPath != expected {
+		t.Errorf("expected files to be exported to '%s', got '%s'", expected, exporter.dirPath)
+	}
+	lines := strings.Split(msg, "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "2 files exported to ") || lines[1] != "  mod.table.t1.csv" || lines[2] != "  mod.card.c1.csv" {
+		t.Errorf("unexpected export message:\n%s", msg)
+	}
+
+	// if there is no data, no files are exported
+	exporter.files = nil
+	msg, err = target.Export(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(msg, "No csv files exported") {
+		t.Errorf("unexpected export message:\n%s", msg)
+	}
+}
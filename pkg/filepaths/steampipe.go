@@ -103,6 +103,16 @@ func EnsureDashboardAssetsDir() string {
 	return ensureSteampipeSubDir(filepath.Join("dashboard", "assets"))
 }
 
+// DashboardServerCertLocation returns the path of the dashboard server certificate generated by steampipe
+func DashboardServerCertLocation() string {
+	return filepath.Join(ensureSteampipeSubDir("dashboard"), constants.DashboardServerCert)
+}
+
+// DashboardServerCertKeyLocation returns the path of the private key of the dashboard server certificate generated by steampipe
+func DashboardServerCertKeyLocation() string {
+	return filepath.Join(ensureSteampipeSubDir("dashboard"), constants.DashboardServerCertKey)
+}
+
 // LegacyDashboardAssetsDir returns the path to the legacy report assets folder
 func LegacyDashboardAssetsDir() string {
 	return steampipeSubDir("report")
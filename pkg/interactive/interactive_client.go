@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -28,8 +30,10 @@ import (
 	"github.com/turbot/steampipe/pkg/interactive/metaquery"
 	"github.com/turbot/steampipe/pkg/query"
 	"github.com/turbot/steampipe/pkg/query/queryhistory"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
 	"github.com/turbot/steampipe/pkg/statushooks"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/inputvars"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/utils"
 	"github.com/turbot/steampipe/pkg/version"
@@ -69,6 +73,15 @@ type InteractiveClient struct {
 	hidePrompt bool
 
 	suggestions *autoCompleteSuggestions
+
+	// the history entry for the line being executed - this is saved once execution is complete
+	activeHistoryEntry *queryhistory.HistoryEntry
+	// the state of the active reverse history search (Ctrl-R) - nil if there is no search in progress
+	historySearch *historySearch
+	// text to populate the prompt with when it is next started
+	nextPromptText string
+	// the rows of the most recent query result - used by .export
+	lastResult *queryresult.BufferedResult
 }
 
 func getHighlighter(theme string) *Highlighter {
@@ -80,7 +93,7 @@ func getHighlighter(theme string) *Highlighter {
 }
 
 func newInteractiveClient(ctx context.Context, initData *query.InitData, result *RunInteractivePromptResult) (*InteractiveClient, error) {
-	interactiveQueryHistory, err := queryhistory.New()
+	interactiveQueryHistory, err := queryhistory.New(viper.GetString(constants.ArgModLocation))
 	if err != nil {
 		return nil, err
 	}
@@ -147,9 +160,6 @@ func (c *InteractiveClient) InteractivePrompt(parentContext context.Context) {
 			// - we must wait for it to shut down and not return immediately
 
 		case <-promptResultChan:
-			// persist saved history
-			//nolint:golint,errcheck // worst case is history is not persisted - not a failure
-			c.interactiveQueryHistory.Persist()
 			// check post-close action
 			if c.afterClose == AfterPromptCloseExit {
 				// clear prompt so any messages/warnings can be displayed without the prompt
@@ -228,6 +238,9 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) {
 			if len(c.interactiveBuffer) > 0 {
 				prefix = ">>  "
 			}
+			if c.historySearch != nil {
+				prefix = c.historySearch.prefix()
+			}
 			if c.hidePrompt {
 				prefix = ""
 			}
@@ -235,13 +248,32 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) {
 		}),
 		prompt.OptionFormatter(c.highlighter.Highlight),
 		prompt.OptionHistory(c.interactiveQueryHistory.Get()),
+		prompt.OptionInitialBufferText(c.nextPromptText),
 		prompt.OptionInputTextColor(prompt.DefaultColor),
 		prompt.OptionPrefixTextColor(prompt.DefaultColor),
 		prompt.OptionMaxSuggestion(20),
+		prompt.OptionCompletionWordSeparator(completionWordSeparator),
 		// Known Key Bindings
 		prompt.OptionAddKeyBind(prompt.KeyBind{
 			Key: prompt.ControlC,
-			Fn:  func(b *prompt.Buffer) { c.breakMultilinePrompt(b) },
+			Fn: func(b *prompt.Buffer) {
+				c.historySearch = nil
+				c.breakMultilinePrompt(b)
+			},
+		}),
+		// reverse history search
+		prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: prompt.ControlR,
+			Fn:  c.reverseSearchHistory,
+		}),
+		// update the reverse history search as the search term is typed
+		prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: prompt.NotDefined,
+			Fn:  c.updateHistorySearch,
+		}),
+		prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: prompt.Backspace,
+			Fn:  c.updateHistorySearch,
 		}),
 		prompt.OptionAddKeyBind(prompt.KeyBind{
 			Key: prompt.ControlD,
@@ -264,11 +296,21 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) {
 		prompt.OptionAddKeyBind(prompt.KeyBind{
 			Key: prompt.Escape,
 			Fn: func(b *prompt.Buffer) {
+				// exit reverse history search, keeping the current match
+				c.historySearch = nil
 				if len(b.Text()) == 0 {
 					c.autocompleteOnEmpty = false
 				}
 			},
 		}),
+		prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: prompt.Left,
+			Fn:  c.endHistorySearch,
+		}),
+		prompt.OptionAddKeyBind(prompt.KeyBind{
+			Key: prompt.Right,
+			Fn:  c.endHistorySearch,
+		}),
 		prompt.OptionAddKeyBind(prompt.KeyBind{
 			Key: prompt.ShiftLeft,
 			Fn:  prompt.GoLeftChar,
@@ -315,6 +357,8 @@ func (c *InteractiveClient) runInteractivePrompt(ctx context.Context) {
 	)
 	// set this to a default
 	c.autocompleteOnEmpty = false
+	// the initial text has been used - clear it
+	c.nextPromptText = ""
 	c.interactivePrompt.RunCtx(ctx)
 
 	return
@@ -346,8 +390,11 @@ func (c *InteractiveClient) executor(ctx context.Context, line string) {
 	c.afterClose = AfterPromptCloseRestart
 
 	line = strings.TrimSpace(line)
+	c.historySearch = nil
 
 	resolvedQuery := c.getQuery(ctx, line)
+	// save the history entry once execution is complete, so the result of the query can be recorded
+	defer c.saveHistoryEntry()
 	if resolvedQuery == nil {
 		// we failed to resolve a query, or are in the middle of a multi-line entry
 		// restart the prompt, DO NOT clear the interactive buffer
@@ -384,6 +431,9 @@ func (c *InteractiveClient) executor(ctx context.Context, line string) {
 }
 
 func (c *InteractiveClient) executeQuery(ctx context.Context, queryCtx context.Context, resolvedQuery *modconfig.ResolvedQuery) {
+	// clear the previous result - if this query fails, there is no result to export
+	c.lastResult = nil
+
 	// if there is a custom search path, wait until the first connection of each plugin has loaded
 	if customSearchPath := c.client().GetCustomSearchPath(); customSearchPath != nil {
 		if err := connection_sync.WaitForSearchPathSchemas(ctx, c.client(), customSearchPath); err != nil {
@@ -392,17 +442,71 @@ func (c *InteractiveClient) executeQuery(ctx context.Context, queryCtx context.C
 		}
 	}
 
+	// if the query returns more than max-rows rows, ask the user whether to fetch the remaining rows
+	queryCtx = db_common.AddMaxRowsConfirmToContext(queryCtx, func(ctx context.Context, maxRows int) bool {
+		return confirmFetchRemainingRows(ctx, &inputvars.UIInput{}, maxRows)
+	})
+
 	t := time.Now()
 	result, err := c.client().Execute(queryCtx, resolvedQuery.ExecuteSQL, resolvedQuery.Args...)
 	if err != nil {
-		error_helpers.ShowError(ctx, error_helpers.HandleCancelError(err))
+		err = error_helpers.HandleCancelError(err)
+		error_helpers.ShowError(ctx, err)
 		// if timing flag is enabled, show the time taken for the query to fail
 		if cmdconfig.Viper().GetBool(constants.ArgTiming) {
 			display.DisplayErrorTiming(t)
 		}
+		if c.activeHistoryEntry != nil {
+			c.activeHistoryEntry.Error = err.Error()
+		}
 	} else {
-		c.promptResult.Streamer.StreamResult(result)
+		// StreamResult waits for the result to be displayed
+		c.promptResult.Streamer.StreamResult(c.recordResult(result))
 	}
+	if c.activeHistoryEntry != nil {
+		c.activeHistoryEntry.Duration = time.Since(t)
+	}
+}
+
+// recordResult returns a Result which streams the rows of the given result, recording the row count and any error
+// in the active history entry, and retaining the rows so the result can be exported with .export
+func (c *InteractiveClient) recordResult(result *queryresult.Result) *queryresult.Result {
+	entry := c.activeHistoryEntry
+
+	rowChan := make(chan *queryresult.RowResult)
+	res := &queryresult.Result{
+		RowChan:      &rowChan,
+		Cols:         result.Cols,
+		TimingResult: result.TimingResult,
+	}
+	go func() {
+		bufferedResult := &queryresult.BufferedResult{Cols: result.Cols}
+		var rowErr error
+		for row := range *result.RowChan {
+			// update the entry before passing on the row, so it is complete by the time the result has been read
+			if row.Error != nil {
+				rowErr = row.Error
+			} else {
+				bufferedResult.Rows = append(bufferedResult.Rows, row)
+			}
+			if entry != nil {
+				entry.RowCount = len(bufferedResult.Rows)
+				if rowErr != nil {
+					entry.Error = rowErr.Error()
+				}
+			}
+			*res.RowChan <- row
+		}
+		// the result is only known to be truncated once all rows have been read
+		res.Truncated = result.Truncated
+		bufferedResult.Truncated = result.Truncated
+		// the result is only retained if it was read without error
+		if rowErr == nil {
+			c.lastResult = bufferedResult
+		}
+		res.Close()
+	}()
+	return res
 }
 
 func (c *InteractiveClient) getQuery(ctx context.Context, line string) *modconfig.ResolvedQuery {
@@ -413,12 +517,15 @@ func (c *InteractiveClient) getQuery(ctx context.Context, line string) *modconfi
 
 	// store the history (the raw line which was entered)
 	historyEntry := line
+	var historyErr error
 	defer func() {
 		if len(historyEntry) > 0 {
 			// we want to store even if we fail to resolve a query
-			c.interactiveQueryHistory.Push(historyEntry)
+			c.activeHistoryEntry = c.interactiveQueryHistory.Push(historyEntry)
+			if c.activeHistoryEntry != nil && historyErr != nil {
+				c.activeHistoryEntry.Error = historyErr.Error()
+			}
 		}
-
 	}()
 
 	// wait for initialisation to complete so we can access the workspace
@@ -465,12 +572,19 @@ func (c *InteractiveClient) getQuery(ctx context.Context, line string) *modconfi
 
 	// in case of a named query call with params, parse the where clause
 	resolvedQuery, queryProvider, err := c.workspace().ResolveQueryAndArgsFromSQLString(queryString)
+	// if a named query has params with no value, ask the user for them
+	var missingParamsErr *modconfig.MissingParamsError
+	if errors.As(err, &missingParamsErr) {
+		queryProvider = missingParamsErr.QueryProvider
+		resolvedQuery, err = c.promptForMissingParams(ctx, missingParamsErr)
+	}
 	if err != nil {
 		// if we fail to resolve:
 		// - show error but do not return it so we  stay in the prompt
 		// - do not clear history item - we want to store bad entry in history
 		// - clear interactive buffer
 		c.interactiveBuffer = nil
+		historyErr = err
 		error_helpers.ShowError(ctx, err)
 		return nil
 	}
@@ -540,6 +654,10 @@ func (c *InteractiveClient) executeMetaquery(ctx context.Context, query string)
 		SearchPath:      client.GetRequiredSessionSearchPath(),
 		Prompt:          c.interactivePrompt,
 		ClosePrompt:     func() { c.afterClose = AfterPromptCloseExit },
+		History:         c.interactiveQueryHistory,
+		LoadQuery:       func(query string) { c.nextPromptText = query },
+		LastResult:      c.lastResult,
+		ExportManager:   c.initData.ExportManager,
 		ConnectionState: connectionState,
 	})
 }
@@ -613,13 +731,23 @@ func (c *InteractiveClient) queryCompleter(d prompt.Document) []prompt.Suggest {
 		})
 		s = append(s, suggestions...)
 	default:
-		if queryInfo := getQueryInfo(text); queryInfo.EditingTable {
+		// include any previous lines of a multi-line query
+		previousLines := strings.ToLower(strings.Join(c.interactiveBuffer, " "))
+		textBeforeCursor := previousLines + " " + strings.ToLower(d.TextBeforeCursor())
+		queryText := previousLines + " " + strings.ToLower(d.Text)
+
+		queryInfo := getQueryInfo(textBeforeCursor, queryText)
+		switch {
+		case queryInfo.EditingTable:
 			tableSuggestions := c.getTableAndConnectionSuggestions(lastWord(text))
 			s = append(s, tableSuggestions...)
+		case queryInfo.EditingColumn:
+			columnSuggestions := c.getColumnSuggestions(queryInfo.Tables)
+			s = append(s, columnSuggestions...)
 		}
 	}
 
-	return prompt.FilterHasPrefix(s, d.GetWordBeforeCursor(), true)
+	return prompt.FilterHasPrefix(s, d.GetWordBeforeCursorUntilSeparator(completionWordSeparator), true)
 }
 
 func (c *InteractiveClient) getFirstWordSuggestions(word string) []prompt.Suggest {
@@ -662,6 +790,54 @@ func (c *InteractiveClient) getTableAndConnectionSuggestions(word string) []prom
 	return t
 }
 
+// getColumnSuggestions returns suggestions for the columns of the given tables
+func (c *InteractiveClient) getColumnSuggestions(tables []string) []prompt.Suggest {
+	// use lookup to avoid dupes where tables share column names
+	columnLookup := make(map[string]struct{})
+	var s []prompt.Suggest
+	for _, tableName := range tables {
+		tableSchema, ok := c.getTableSchema(tableName)
+		if !ok {
+			continue
+		}
+		for columnName, column := range tableSchema.Columns {
+			if _, alreadyAdded := columnLookup[columnName]; alreadyAdded {
+				continue
+			}
+			columnLookup[columnName] = struct{}{}
+			suggestion := c.newSuggestion(column.Type, column.Description, columnName)
+			suggestion.Output = sanitiseTableName(columnName)
+			s = append(s, suggestion)
+		}
+	}
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Text < s[j].Text
+	})
+	return s
+}
+
+// getTableSchema returns the schema of the given table
+// unqualified names are resolved using the search path
+func (c *InteractiveClient) getTableSchema(tableName string) (db_common.TableSchema, bool) {
+	if c.schemaMetadata == nil {
+		return db_common.TableSchema{}, false
+	}
+	tableName = strings.ReplaceAll(tableName, `"`, "")
+	if parts := strings.SplitN(tableName, ".", 2); len(parts) == 2 {
+		tableSchema, ok := c.schemaMetadata.Schemas[parts[0]][parts[1]]
+		return tableSchema, ok
+	}
+
+	// temporary tables take precedence over the search path
+	searchPath := append([]string{c.schemaMetadata.TemporarySchemaName}, c.client().GetRequiredSessionSearchPath()...)
+	for _, schemaName := range searchPath {
+		if tableSchema, ok := c.schemaMetadata.Schemas[schemaName][tableName]; ok {
+			return tableSchema, true
+		}
+	}
+	return db_common.TableSchema{}, false
+}
+
 func (c *InteractiveClient) newSuggestion(itemType string, description string, name string) prompt.Suggest {
 	if description != "" {
 		itemType += fmt.Sprintf(": %s", description)
@@ -732,7 +908,7 @@ func (c *InteractiveClient) handlePostgresNotification(ctx context.Context, noti
 func (c *InteractiveClient) handleErrorsAndWarningsNotification(ctx context.Context, notification *steampipeconfig.ErrorsAndWarningsNotification) {
 	log.Printf("[TRACE] handleErrorsAndWarningsNotification")
 	output := viper.Get(constants.ArgOutput)
-	if output == constants.OutputFormatJSON || output == constants.OutputFormatCSV {
+	if output == constants.OutputFormatJSON || output == constants.OutputFormatJSONL || output == constants.OutputFormatYAML || output == constants.OutputFormatCSV {
 		return
 	}
 
@@ -0,0 +1,108 @@
+package interactive
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/c-bata/go-prompt"
+)
+
+// historySearch is the state of a reverse history search (Ctrl-R)
+type historySearch struct {
+	term string
+	// the index in the history of the current match - the next search starts before this
+	matchIdx int
+	// the text the search last put in the buffer
+	text   string
+	failed bool
+}
+
+func (s *historySearch) prefix() string {
+	if s.failed {
+		return fmt.Sprintf("(failed reverse-i-search)'%s': ", s.term)
+	}
+	return fmt.Sprintf("(reverse-i-search)'%s': ", s.term)
+}
+
+// reverseSearchHistory starts a reverse history search for the text in the buffer
+// or, if a search is in progress, moves to the next older match
+func (c *InteractiveClient) reverseSearchHistory(b *prompt.Buffer) {
+	historyLength := len(c.interactiveQueryHistory.Entries())
+	if c.historySearch == nil {
+		c.historySearch = &historySearch{
+			term:     b.Text(),
+			matchIdx: historyLength,
+			text:     b.Text(),
+		}
+		if c.historySearch.term == "" {
+			// nothing to search for yet - wait for the term to be typed
+			return
+		}
+	}
+	c.findHistoryMatch(b, c.historySearch.matchIdx)
+}
+
+// updateHistorySearch is called after a key is typed or deleted
+// if a search is in progress, it updates the search term and searches again from the most recent entry
+func (c *InteractiveClient) updateHistorySearch(b *prompt.Buffer) {
+	s := c.historySearch
+	if s == nil {
+		return
+	}
+
+	// the key will already have modified the buffer - determine how the search term changed
+	newText := []rune(b.Text())
+	oldText := []rune(s.text)
+	if added := len(newText) - len(oldText); added > 0 {
+		// the typed text was inserted before the cursor
+		textBeforeCursor := []rune(b.Document().TextBeforeCursor())
+		s.term += string(textBeforeCursor[len(textBeforeCursor)-added:])
+	} else if term := []rune(s.term); len(term) > 0 {
+		s.term = string(term[:len(term)-1])
+	}
+
+	if s.term == "" {
+		s.failed = false
+		s.matchIdx = len(c.interactiveQueryHistory.Entries())
+		setBufferText(b, "")
+		s.text = ""
+		return
+	}
+	c.findHistoryMatch(b, len(c.interactiveQueryHistory.Entries()))
+}
+
+// endHistorySearch ends any history search in progress, leaving the current match in the buffer
+func (c *InteractiveClient) endHistorySearch(*prompt.Buffer) {
+	c.historySearch = nil
+}
+
+// findHistoryMatch puts the most recent history entry before the given index which matches the search term into the buffer
+// if there is no match, the previous match is kept
+func (c *InteractiveClient) findHistoryMatch(b *prompt.Buffer, before int) {
+	s := c.historySearch
+	entry, idx := c.interactiveQueryHistory.Search(s.term, before)
+	s.failed = entry == nil
+	if entry != nil {
+		s.matchIdx = idx
+		s.text = entry.Query
+	}
+	setBufferText(b, s.text)
+}
+
+func setBufferText(b *prompt.Buffer, text string) {
+	b.DeleteBeforeCursor(len([]rune(b.Document().TextBeforeCursor())))
+	b.Delete(len([]rune(b.Document().TextAfterCursor())))
+	b.InsertText(text, false, true)
+}
+
+// saveHistoryEntry appends the history entry for the executed line to the history file
+func (c *InteractiveClient) saveHistoryEntry() {
+	if c.activeHistoryEntry == nil {
+		return
+	}
+	if err := c.interactiveQueryHistory.Save(c.activeHistoryEntry); err != nil {
+		// worst case is history is not persisted - not a failure
+		log.Printf("[WARN] failed to save query history: %s", err.Error())
+	}
+	c.activeHistoryEntry = nil
+}
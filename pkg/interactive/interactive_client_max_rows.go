@@ -0,0 +1,35 @@
+package interactive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/turbot/steampipe/pkg/statushooks"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/inputvars"
+)
+
+// confirmFetchRemainingRows asks the user whether to fetch the remaining rows of a query which has returned
+// the max-rows limit - unless the user confirms, the result is truncated
+func confirmFetchRemainingRows(ctx context.Context, uiInput *inputvars.UIInput, maxRows int) bool {
+	// hide the spinner while waiting for input
+	statushooks.Done(ctx)
+
+	value, err := uiInput.Input(ctx, &terraform.InputOpts{
+		Id:          "max_rows",
+		Query:       fmt.Sprintf("The query has returned more than %d rows (see --max-rows). Fetch the remaining rows?", maxRows),
+		Description: "Enter 'y' to fetch all rows, or anything else to truncate the result",
+		Default:     "n",
+	})
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "y", "yes":
+		statushooks.Show(ctx)
+		statushooks.SetStatus(ctx, "Loading results…")
+		return true
+	}
+	return false
+}
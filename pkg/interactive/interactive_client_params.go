@@ -0,0 +1,57 @@
+package interactive
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform/terraform"
+	typehelpers "github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/inputvars"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// promptForMissingParams asks the user for a value for each param of a named query which has no arg and no default
+// then resolves the query using these values
+func (c *InteractiveClient) promptForMissingParams(ctx context.Context, missingParamsErr *modconfig.MissingParamsError) (*modconfig.ResolvedQuery, error) {
+	fmt.Printf("%s requires values for the following parameters\n\n", missingParamsErr.QueryProvider.Name())
+
+	uiInput := &inputvars.UIInput{}
+	values := make(map[string]any, len(missingParamsErr.Params))
+	for _, param := range missingParamsErr.Params {
+		value, err := promptForParam(ctx, uiInput, param)
+		if err != nil {
+			return nil, err
+		}
+		values[param.ShortName] = value
+	}
+	return missingParamsErr.ResolveWithValues(values)
+}
+
+// promptForParam asks the user for a value for the param, asking again until a valid value is entered
+func promptForParam(ctx context.Context, uiInput *inputvars.UIInput, param *modconfig.ParamDef) (any, error) {
+	description := typehelpers.SafeString(param.Description)
+	if param.Default == nil {
+		if description != "" {
+			description += "\n"
+		}
+		description += "(numbers, booleans and arrays are passed as that type - quote a value to pass it as a string)"
+	}
+
+	for {
+		rawValue, err := uiInput.Input(ctx, &terraform.InputOpts{
+			Id:          param.UnqualifiedName,
+			Query:       param.UnqualifiedName,
+			Description: description,
+			Default:     typehelpers.SafeString(param.Default),
+		})
+		if err != nil {
+			return nil, err
+		}
+		value, err := param.ParseValue(rawValue)
+		if err == nil {
+			return value, nil
+		}
+		error_helpers.ShowError(ctx, err)
+	}
+}
@@ -0,0 +1,92 @@
+package interactive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/inputvars"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// failingClient is a db client whose queries always fail
+type failingClient struct {
+	db_common.Client
+}
+
+func (f *failingClient) GetCustomSearchPath() []string {
+	return nil
+}
+
+func (f *failingClient) Execute(context.Context, string, ...any) (*queryresult.Result, error) {
+	return nil, errors.New("relation does not exist")
+}
+
+func TestExecuteQueryClearsLastResultOnFailure(t *testing.T) {
+	initData := &query.InitData{}
+	initData.Client = &failingClient{}
+	c := &InteractiveClient{
+		initData:   initData,
+		lastResult: &queryresult.BufferedResult{},
+	}
+
+	c.executeQuery(context.Background(), context.Background(), &modconfig.ResolvedQuery{ExecuteSQL: "select * from missing"})
+
+	// .export must not export the result of the previous query
+	if c.lastResult != nil {
+		t.Errorf("expected the last result to be cleared after a failed query")
+	}
+}
+
+func TestRecordResultTruncated(t *testing.T) {
+	source := queryresult.NewResult([]*queryresult.ColumnDef{{Name: "id", DataType: "INT8"}})
+	go func() {
+		source.StreamRow([]any{int64(1)})
+		source.StreamRow([]any{int64(2)})
+		// the result is truncated once the max-rows limit is reached
+		source.Truncated = true
+		source.Close()
+	}()
+
+	c := &InteractiveClient{}
+	res := c.recordResult(source)
+	rowCount := 0
+	for range *res.RowChan {
+		rowCount++
+	}
+
+	if rowCount != 2 {
+		t.Errorf("expected 2 rows, got %d", rowCount)
+	}
+	// the truncation warning is shown if the displayed result is truncated
+	if !res.Truncated {
+		t.Errorf("expected the recorded result to be truncated")
+	}
+	if c.lastResult == nil || !c.lastResult.Truncated {
+		t.Errorf("expected the last result to be truncated")
+	}
+}
+
+func TestConfirmFetchRemainingRows(t *testing.T) {
+	tests := map[string]bool{
+		"y\n":   true,
+		"Yes\n": true,
+		"n\n":   false,
+		// the default is to truncate the result
+		"\n":      false,
+		"maybe\n": false,
+	}
+	for input, expected := range tests {
+		t.Run(strings.TrimSpace(input), func(t *testing.T) {
+			uiInput := &inputvars.UIInput{Reader: strings.NewReader(input), Writer: io.Discard}
+			if got := confirmFetchRemainingRows(context.Background(), uiInput, 100); got != expected {
+				t.Errorf("confirmFetchRemainingRows() with input %q = %v, expected %v", input, got, expected)
+			}
+		})
+	}
+}
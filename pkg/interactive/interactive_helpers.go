@@ -2,68 +2,116 @@ package interactive
 
 import (
 	"strings"
-
-	"github.com/turbot/go-kit/helpers"
 )
 
+// the characters which separate the word being completed from the preceding text
+const completionWordSeparator = " ,()"
+
 type queryCompletionInfo struct {
-	Table        string
-	EditingTable bool
+	// the tables referenced by the query - used to suggest column names
+	Tables        []string
+	EditingTable  bool
+	EditingColumn bool
 }
 
-func getQueryInfo(text string) *queryCompletionInfo {
-	table := getTable(text)
-	prevWord := getPreviousWord(text)
+// keywords after which a table name is expected
+var tableKeywords = map[string]struct{}{"from": {}, "join": {}}
 
-	return &queryCompletionInfo{
-		Table:        table,
-		EditingTable: isEditingTable(prevWord),
-	}
-}
+// keywords after which a column name is expected
+var columnKeywords = map[string]struct{}{"select": {}, "distinct": {}, "where": {}, "by": {}, "and": {}, "or": {}, "on": {}, "having": {}}
 
-func isEditingTable(prevWord string) bool {
-	var editingTable = prevWord == "from"
-	return editingTable
+// other keywords - these end a table or column list
+var clauseKeywords = map[string]struct{}{
+	"group": {}, "order": {}, "limit": {}, "offset": {}, "union": {}, "intersect": {}, "except": {}, "with": {}, "using": {},
+	"left": {}, "right": {}, "inner": {}, "outer": {}, "full": {}, "cross": {}, "natural": {}, "lateral": {},
 }
 
-func getTable(text string) string {
-	// split on space and remove empty results - they occur if there is a double space
-	split := helpers.RemoveFromStringSlice(strings.Split(text, " "), "")
+// getQueryInfo determines what is being completed
+// textBeforeCursor is used to determine the context of the word being typed,
+// and queryText (the full query) to determine which tables are referenced
+func getQueryInfo(textBeforeCursor, queryText string) *queryCompletionInfo {
+	info := &queryCompletionInfo{Tables: getTables(queryText)}
 
-	for idx, word := range split {
-		if word == "from" {
-			if idx+1 < len(split) {
-				return split[idx+1]
-			}
+	tokens := tokenize(textBeforeCursor)
+	// exclude the word currently being typed
+	if len(tokens) > 0 && !strings.ContainsAny(textBeforeCursor[len(textBeforeCursor)-1:], completionWordSeparator) {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return info
+	}
+	prevToken := tokens[len(tokens)-1]
+
+	// find the most recent keyword
+	for i := len(tokens) - 1; i >= 0; i-- {
+		token := tokens[i]
+		if _, ok := tableKeywords[token]; ok {
+			// only complete the table names themselves, not their aliases
+			info.EditingTable = prevToken == token || prevToken == ","
+			break
+		}
+		if _, ok := columnKeywords[token]; ok {
+			// do not complete a column alias
+			info.EditingColumn = prevToken != "as"
+			break
+		}
+		if isKeyword(token) {
+			break
 		}
 	}
-	return ""
+	return info
 }
 
-func getPreviousWord(text string) string {
-	// create a new document up the previous space
-	finalSpace := strings.LastIndex(text, " ")
-	if finalSpace == -1 {
-		return ""
-	}
-	lastNotSpace := lastIndexByteNot(text[:finalSpace], ' ')
-	if lastNotSpace == -1 {
-		return ""
-	}
-	prevSpace := strings.LastIndex(text[:lastNotSpace], " ")
-	if prevSpace == -1 {
-		return ""
+// getTables returns the names of all tables referenced in from and join clauses of the query
+func getTables(text string) []string {
+	tokens := tokenize(text)
+	var tables []string
+	for i := 0; i < len(tokens); i++ {
+		if _, ok := tableKeywords[tokens[i]]; !ok {
+			continue
+		}
+		// a from clause may contain a comma separated list of tables, each with an optional alias
+		for i+1 < len(tokens) {
+			name := tokens[i+1]
+			// stop if this is a subquery
+			if name == "(" || isKeyword(name) {
+				break
+			}
+			tables = append(tables, name)
+			i++
+			// skip the alias
+			if i+1 < len(tokens) && tokens[i+1] == "as" {
+				i++
+			}
+			if i+1 < len(tokens) && !isKeyword(tokens[i+1]) && !strings.ContainsAny(tokens[i+1], completionWordSeparator) {
+				i++
+			}
+			if i+1 < len(tokens) && tokens[i+1] == "," {
+				i++
+				continue
+			}
+			break
+		}
 	}
-	return text[prevSpace+1 : lastNotSpace+1]
+	return tables
 }
 
-func lastIndexByteNot(s string, c byte) int {
-	for i := len(s) - 1; i >= 0; i-- {
-		if s[i] != c {
-			return i
+func isKeyword(token string) bool {
+	for _, keywords := range []map[string]struct{}{tableKeywords, columnKeywords, clauseKeywords} {
+		if _, ok := keywords[token]; ok {
+			return true
 		}
 	}
-	return -1
+	return false
+}
+
+// tokenize splits the text into words, returning commas and parentheses as separate tokens
+func tokenize(text string) []string {
+	text = strings.ReplaceAll(text, ";", " ")
+	for _, sep := range []string{",", "(", ")"} {
+		text = strings.ReplaceAll(text, sep, " "+sep+" ")
+	}
+	return strings.Fields(text)
 }
 
 // if there are no spaces this is the first word
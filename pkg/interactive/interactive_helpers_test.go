@@ -0,0 +1,40 @@
+package interactive
+
+import (
+	"reflect"
+	"testing"
+)
+
+type queryInfoExpected struct {
+	tables        []string
+	editingTable  bool
+	editingColumn bool
+}
+
+func TestGetQueryInfo(t *testing.T) {
+	cases := map[string]queryInfoExpected{
+		`select * from aws_s`:                             {tables: []string{"aws_s"}, editingTable: true},
+		`select * from aws_s3_bucket b, aws.aws_ec2_ins`:  {tables: []string{"aws_s3_bucket", "aws.aws_ec2_ins"}, editingTable: true},
+		`select * from aws_s3_bucket as b`:                {tables: []string{"aws_s3_bucket"}},
+		`select na`:                                       {editingColumn: true},
+		`select name, a`:                                  {editingColumn: true},
+		`select count(a`:                                  {editingColumn: true},
+		`select name as n`:                                {},
+		`select * from aws_s3_bucket where `:              {tables: []string{"aws_s3_bucket"}, editingColumn: true},
+		`select * from a join b on a.id = b.id order by `: {tables: []string{"a", "b"}, editingColumn: true},
+		`select * from aws_s3_bucket limit `:              {tables: []string{"aws_s3_bucket"}},
+	}
+
+	for input, expected := range cases {
+		info := getQueryInfo(input, input)
+		if !reflect.DeepEqual(info.Tables, expected.tables) {
+			t.Errorf("%s: tables %v != %v", input, info.Tables, expected.tables)
+		}
+		if info.EditingTable != expected.editingTable {
+			t.Errorf("%s: editingTable %v != %v", input, info.EditingTable, expected.editingTable)
+		}
+		if info.EditingColumn != expected.editingColumn {
+			t.Errorf("%s: editingColumn %v != %v", input, info.EditingColumn, expected.editingColumn)
+		}
+	}
+}
@@ -85,14 +85,29 @@ func init() {
 			},
 			completer: completerFromArgsOf(constants.CmdTiming),
 		},
+		constants.CmdExplain: {
+			title:       "explain",
+			handler:     setExplain,
+			validator:   booleanValidator(constants.CmdExplain, validatorFromArgsOf(constants.CmdExplain)),
+			description: "Enable or disable display of the query plan and the foreign table scans made by each query",
+			args: []metaQueryArg{
+				{value: constants.ArgOn, description: "Display the query plan and foreign table scans after every query"},
+				{value: constants.ArgOff, description: "Turn off explain mode"},
+			},
+			completer: completerFromArgsOf(constants.CmdExplain),
+		},
 		constants.CmdOutput: {
 			title:       constants.CmdOutput,
 			handler:     setViperConfigFromArg(constants.ArgOutput),
 			validator:   composeValidator(exactlyNArgs(1), validatorFromArgsOf(constants.CmdOutput)),
-			description: "Set output format: csv, json, table or line",
+			description: "Set output format: csv, json, jsonl, md, html, yaml, table or line",
 			args: []metaQueryArg{
 				{value: constants.OutputFormatJSON, description: "Set output to JSON"},
+				{value: constants.OutputFormatJSONL, description: "Set output to JSON Lines"},
 				{value: constants.OutputFormatCSV, description: "Set output to CSV"},
+				{value: constants.OutputFormatMarkdown, description: "Set output to Markdown"},
+				{value: constants.OutputFormatHTML, description: "Set output to HTML"},
+				{value: constants.OutputFormatYAML, description: "Set output to YAML"},
 				{value: constants.OutputFormatTable, description: "Set output to Table"},
 				{value: constants.OutputFormatLine, description: "Set output to Line"},
 			},
@@ -161,5 +176,23 @@ func init() {
 			},
 			completer: completerFromArgsOf(constants.CmdAutoComplete),
 		},
+		constants.CmdHistory: {
+			title:       constants.CmdHistory,
+			handler:     showHistory,
+			validator:   atLeastNArgs(0),
+			description: "List the query history, optionally filtered, or load query n from the history into the prompt",
+		},
+		constants.CmdExport: {
+			title:       constants.CmdExport,
+			handler:     exportResult,
+			validator:   exactlyNArgs(1),
+			description: "Export the most recent query result to file, e.g. '.export result.csv' (supported formats: csv, json, jsonl, md, html, yaml)",
+		},
+		constants.CmdWatch: {
+			title:       constants.CmdWatch,
+			handler:     watchQuery,
+			validator:   atLeastNArgs(2),
+			description: "Re-run a query at an interval, highlighting changed rows, e.g. '.watch 30s select ...' (press Ctrl-C to stop)",
+		},
 	}
 }
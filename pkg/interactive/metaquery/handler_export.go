@@ -0,0 +1,37 @@
+package metaquery
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+// .export
+// export the most recent query result - the format is inferred from the file extension
+func exportResult(ctx context.Context, input *HandlerInput) error {
+	if input.LastResult == nil {
+		return sperr.New("there is no query result to export")
+	}
+
+	target := input.args()[0]
+	// snapshots are only available in batch mode
+	if helpers.StringSliceContains([]string{constants.OutputFormatSnapshot, constants.OutputFormatSnapshotShort}, target) || path.Ext(target) == constants.SnapshotExtension {
+		return sperr.New("snapshots cannot be exported from the interactive prompt")
+	}
+
+	result := input.LastResult.Replay()
+	// the export may fail before reading the result
+	defer result.Drain()
+	// if a format name is used rather than a file name, the file is named after the query
+	exportMsg, err := input.ExportManager.DoExport(ctx, "query", result, []string{target})
+	if err != nil {
+		return err
+	}
+	fmt.Println(strings.Join(exportMsg, "\n"))
+	return nil
+}
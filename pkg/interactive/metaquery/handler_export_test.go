@@ -0,0 +1,76 @@
+package metaquery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/export"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+func testExportInput(t *testing.T, query string, lastResult *queryresult.BufferedResult) *HandlerInput {
+	exportManager := export.NewManager()
+	for _, e := range display.QueryResultExporters() {
+		if err := exportManager.Register(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &HandlerInput{Query: query, LastResult: lastResult, ExportManager: exportManager}
+}
+
+func TestExportResult(t *testing.T) {
+	lastResult := &queryresult.BufferedResult{
+		Cols: []*queryresult.ColumnDef{{Name: "name", DataType: "TEXT"}},
+		Rows: []*queryresult.RowResult{{Data: []interface{}{"a"}}, {Data: []interface{}{"b"}}},
+	}
+	target := filepath.Join(t.TempDir(), "result.csv")
+
+	if err := exportResult(context.Background(), testExportInput(t, ".export "+target, lastResult)); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "name\na\nb\n"; string(content) != expected {
+		t.Errorf("expected export:\n%s\ngot:\n%s", expected, string(content))
+	}
+
+	// the result may be exported more than once
+	target = filepath.Join(filepath.Dir(target), "result.jsonl")
+	if err := exportResult(context.Background(), testExportInput(t, ".export "+target, lastResult)); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(target); string(content) != "{\"name\":\"a\"}\n{\"name\":\"b\"}\n" {
+		t.Errorf("unexpected jsonl export:\n%s", string(content))
+	}
+}
+
+func TestExportResultErrors(t *testing.T) {
+	lastResult := &queryresult.BufferedResult{Cols: []*queryresult.ColumnDef{{Name: "name", DataType: "TEXT"}}}
+	dir := t.TempDir()
+	cases := map[string]struct {
+		query      string
+		lastResult *queryresult.BufferedResult
+		expected   string
+	}{
+		"no result":      {query: ".export " + filepath.Join(dir, "result.csv"), expected: "there is no query result to export"},
+		"snapshot":       {query: ".export snapshot", lastResult: lastResult, expected: "snapshots cannot be exported"},
+		"snapshot file":  {query: ".export " + filepath.Join(dir, "result.sps"), lastResult: lastResult, expected: "snapshots cannot be exported"},
+		"unknown format": {query: ".export " + filepath.Join(dir, "result.xyz"), lastResult: lastResult, expected: "xyz"},
+	}
+	for name, test := range cases {
+		err := exportResult(context.Background(), testExportInput(t, test.query, test.lastResult))
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: expected error containing '%s', got '%s'", name, test.expected, err.Error())
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package metaquery
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/query/queryhistory"
+)
+
+// the maximum number of entries listed by .history
+const historyListSize = 50
+
+// .history
+// list the query history - if a filter is passed, only list queries containing it
+// if a number is passed, load that entry into the prompt so it can be re-run
+func showHistory(_ context.Context, input *HandlerInput) error {
+	entries := input.History.Entries()
+	filter := strings.Join(input.args(), " ")
+
+	if n, err := strconv.Atoi(filter); err == nil {
+		if n < 1 || n > len(entries) {
+			return sperr.New("there is no history entry %d", n)
+		}
+		input.LoadQuery(entries[n-1].Query)
+		return nil
+	}
+
+	header := []string{"#", "time", "duration", "rows", "query"}
+	var rows [][]string
+	for i, entry := range entries {
+		if !strings.Contains(strings.ToLower(entry.Query), strings.ToLower(filter)) {
+			continue
+		}
+		rows = append(rows, historyRow(i+1, entry))
+	}
+	if len(rows) == 0 {
+		fmt.Println("No matching queries found.")
+		return nil
+	}
+	matchCount := len(rows)
+	if matchCount > historyListSize {
+		rows = rows[matchCount-historyListSize:]
+	}
+
+	display.ShowWrappedTable(header, rows, &display.ShowWrappedTableOptions{AutoMerge: false})
+	if matchCount > historyListSize {
+		fmt.Printf("\nShowing the most recent %d of %d queries.\n", historyListSize, matchCount)
+	}
+	fmt.Printf(`
+To re-run a query, run %s
+`, constants.Bold(".history {n}"))
+	return nil
+}
+
+func historyRow(idx int, entry *queryhistory.HistoryEntry) []string {
+	var timestamp, duration string
+	// entries migrated from the legacy history file have no details
+	if !entry.Timestamp.IsZero() {
+		timestamp = entry.Timestamp.Local().Format("2006-01-02 15:04:05")
+		duration = entry.Duration.Round(time.Millisecond).String()
+	}
+	rows := strconv.Itoa(entry.RowCount)
+	if entry.Error != "" {
+		rows = "error"
+	}
+	// show multi-line queries on a single line
+	query := strings.Join(strings.Fields(entry.Query), " ")
+	return []string{strconv.Itoa(idx), timestamp, duration, rows, query}
+}
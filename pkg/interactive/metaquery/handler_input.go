@@ -3,6 +3,9 @@ package metaquery
 import (
 	"github.com/c-bata/go-prompt"
 	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/export"
+	"github.com/turbot/steampipe/pkg/query/queryhistory"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
 )
 
@@ -16,6 +19,12 @@ type HandlerInput struct {
 	Query           string
 	ConnectionState steampipeconfig.ConnectionStateMap
 	SearchPath      []string
+	History         *queryhistory.QueryHistory
+	// LoadQuery populates the prompt with the given query when it is next shown
+	LoadQuery func(query string)
+	// the most recent query result - nil if there is no result (or the query failed)
+	LastResult    *queryresult.BufferedResult
+	ExportManager *export.Manager
 }
 
 func (h *HandlerInput) args() []string {
@@ -0,0 +1,128 @@
+package metaquery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/display"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+// the shortest interval .watch will re-run a query at
+const minWatchInterval = time.Second
+
+// .watch
+// re-run a query at the given interval, redrawing the result and highlighting rows which changed since the last run
+// the watch runs until the query context is cancelled (by Ctrl-C)
+func watchQuery(ctx context.Context, input *HandlerInput) error {
+	interval, query, err := parseWatchArgs(input)
+	if err != nil {
+		return err
+	}
+
+	restoreCacheTtl := overrideCacheTtl(input.Client.ServerSettings(), interval)
+	defer restoreCacheTtl()
+
+	var previousRows map[string]struct{}
+	for {
+		result, err := input.Client.ExecuteSync(ctx, query)
+		if ctx.Err() != nil {
+			// the watch was cancelled
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		previousRows = showWatchResult(query, interval, result, previousRows)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// parseWatchArgs returns the interval and the query of a .watch command
+func parseWatchArgs(input *HandlerInput) (time.Duration, string, error) {
+	intervalArg := input.args()[0]
+	interval, err := time.ParseDuration(intervalArg)
+	if err != nil {
+		return 0, "", sperr.WrapWithMessage(err, "invalid interval '%s' - valid values are durations such as 30s or 5m", intervalArg)
+	}
+	if interval < minWatchInterval {
+		return 0, "", sperr.New("interval must be at least %s", minWatchInterval)
+	}
+
+	// the query is everything after the interval (do not use the parsed args, to preserve the whitespace in the query)
+	query := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input.Query), constants.CmdWatch))
+	query = strings.TrimSpace(strings.TrimPrefix(query, intervalArg))
+	return interval, query, nil
+}
+
+// showWatchResult clears the screen and displays the result, highlighting the rows which are not in previousRows
+// it returns the rows of this result, to compare the next result to
+func showWatchResult(query string, interval time.Duration, result *queryresult.SyncQueryResult, previousRows map[string]struct{}) map[string]struct{} {
+	rows, highlight, currentRows := getWatchRows(result, previousRows)
+
+	display.ClearScreen()
+	fmt.Printf("Every %s: %s\t%s\n\n", interval, strings.Join(strings.Fields(query), " "), time.Now().Format(time.DateTime))
+	display.ShowHighlightedTable(display.ColumnNames(result.Cols), rows, highlight)
+	fmt.Printf("\nPress %s to stop.\n", constants.Bold("Ctrl-C"))
+	return currentRows
+}
+
+// getWatchRows returns the display values of the rows of the result, whether each row should be highlighted
+// because it is not in previousRows, and the set of rows of this result
+func getWatchRows(result *queryresult.SyncQueryResult, previousRows map[string]struct{}) ([][]string, []bool, map[string]struct{}) {
+	currentRows := make(map[string]struct{}, len(result.Rows))
+	rows := make([][]string, len(result.Rows))
+	highlight := make([]bool, len(result.Rows))
+	for i, r := range result.Rows {
+		row, _ := display.ColumnValuesAsString(r.(*queryresult.RowResult).Data, result.Cols)
+		key := strings.Join(row, "\x00")
+		currentRows[key] = struct{}{}
+		rows[i] = row
+		// nothing is highlighted on the first run
+		if previousRows != nil {
+			_, unchanged := previousRows[key]
+			highlight[i] = !unchanged
+		}
+	}
+	return rows, highlight, currentRows
+}
+
+// overrideCacheTtl lowers the cache TTL to the watch interval, if it is longer,
+// so each run of the query returns fresh data rather than cached results
+// the TTL is applied to the session each time it is acquired - the returned function restores it
+func overrideCacheTtl(serverSettings *db_common.ServerSettings, interval time.Duration) func() {
+	// the TTL to restore is the client setting - if there is none, it is unset again (by setting nil)
+	var currentTtl int
+	var restoreTtl any
+	switch {
+	case viper.IsSet(constants.ArgCacheTtl):
+		clientTtl := viper.GetInt(constants.ArgCacheTtl)
+		restoreTtl = clientTtl
+		currentTtl = getEffectiveCacheTtl(serverSettings, clientTtl)
+	case serverSettings != nil:
+		currentTtl = serverSettings.CacheMaxTtl
+	default:
+		// we do not know the TTL, so could not restore it
+		return func() {}
+	}
+
+	watchTtl := int(interval.Seconds())
+	if currentTtl <= watchTtl {
+		return func() {}
+	}
+	viper.Set(constants.ArgCacheTtl, watchTtl)
+	return func() {
+		viper.Set(constants.ArgCacheTtl, restoreTtl)
+	}
+}
@@ -0,0 +1,181 @@
+package metaquery
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+)
+
+func TestParseWatchArgs(t *testing.T) {
+	cases := map[string]struct {
+		query            string
+		expectedInterval time.Duration
+		expectedQuery    string
+		expectedError    string
+	}{
+		"seconds": {
+			query:            ".watch 30s select 1",
+			expectedInterval: 30 * time.Second,
+			expectedQuery:    "select 1",
+		},
+		"minutes": {
+			query:            ".watch 5m select 1",
+			expectedInterval: 5 * time.Minute,
+			expectedQuery:    "select 1",
+		},
+		"minimum interval": {
+			query:            ".watch 1s select 1",
+			expectedInterval: time.Second,
+			expectedQuery:    "select 1",
+		},
+		// the whitespace in the query (including in string literals) is preserved
+		"whitespace": {
+			query:            "  .watch 10s select name,\n    count(*)\tfrom t where name = 'a  b' group by name  ",
+			expectedInterval: 10 * time.Second,
+			expectedQuery:    "select name,\n    count(*)\tfrom t where name = 'a  b' group by name",
+		},
+		"below minimum interval": {
+			query:         ".watch 500ms select 1",
+			expectedError: "interval must be at least 1s",
+		},
+		"invalid interval": {
+			query:         ".watch 10 select 1",
+			expectedError: "invalid interval '10'",
+		},
+	}
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			interval, query, err := parseWatchArgs(&HandlerInput{Query: test.query})
+			if test.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+					t.Errorf("expected error '%s', got %v", test.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if interval != test.expectedInterval {
+				t.Errorf("expected interval %s, got %s", test.expectedInterval, interval)
+			}
+			if query != test.expectedQuery {
+				t.Errorf("expected query %q, got %q", test.expectedQuery, query)
+			}
+		})
+	}
+}
+
+func testWatchResult(rows ...[]interface{}) *queryresult.SyncQueryResult {
+	result := &queryresult.SyncQueryResult{
+		Cols: []*queryresult.ColumnDef{{Name: "name", DataType: "TEXT"}, {Name: "count", DataType: "INT8"}},
+	}
+	for _, row := range rows {
+		result.Rows = append(result.Rows, &queryresult.RowResult{Data: row})
+	}
+	return result
+}
+
+func TestGetWatchRows(t *testing.T) {
+	// nothing is highlighted on the first run
+	rows, highlight, previousRows := getWatchRows(testWatchResult([]interface{}{"a", int64(1)}, []interface{}{"b", int64(2)}), nil)
+	if expected := [][]string{{"a", "1"}, {"b", "2"}}; !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected rows %v, got %v", expected, rows)
+	}
+	if expected := []bool{false, false}; !reflect.DeepEqual(highlight, expected) {
+		t.Errorf("first run: expected highlight %v, got %v", expected, highlight)
+	}
+
+	// only new or changed rows are highlighted after that
+	_, highlight, previousRows = getWatchRows(testWatchResult(
+		[]interface{}{"a", int64(1)},
+		[]interface{}{"b", int64(3)},
+		[]interface{}{"c", int64(1)},
+	), previousRows)
+	if expected := []bool{false, true, true}; !reflect.DeepEqual(highlight, expected) {
+		t.Errorf("second run: expected highlight %v, got %v", expected, highlight)
+	}
+
+	// rows are compared to the previous run only
+	_, highlight, _ = getWatchRows(testWatchResult(
+		[]interface{}{"b", int64(3)},
+		[]interface{}{"c", int64(1)},
+	), previousRows)
+	if expected := []bool{false, false}; !reflect.DeepEqual(highlight, expected) {
+		t.Errorf("third run: expected highlight %v, got %v", expected, highlight)
+	}
+}
+
+func TestOverrideCacheTtl(t *testing.T) {
+	cases := map[string]struct {
+		clientTtl      *int
+		serverSettings *db_common.ServerSettings
+		// the client TTL while the watch runs and after it is restored (nil means unset)
+		expectedWatchTtl   *int
+		expectedRestoreTtl *int
+	}{
+		"client ttl longer than interval": {
+			clientTtl:          intPtr(600),
+			serverSettings:     &db_common.ServerSettings{CacheMaxTtl: 300},
+			expectedWatchTtl:   intPtr(10),
+			expectedRestoreTtl: intPtr(600),
+		},
+		"client ttl shorter than interval": {
+			clientTtl:          intPtr(5),
+			serverSettings:     &db_common.ServerSettings{CacheMaxTtl: 300},
+			expectedWatchTtl:   intPtr(5),
+			expectedRestoreTtl: intPtr(5),
+		},
+		"server ttl shorter than interval": {
+			clientTtl:          intPtr(600),
+			serverSettings:     &db_common.ServerSettings{CacheMaxTtl: 5},
+			expectedWatchTtl:   intPtr(600),
+			expectedRestoreTtl: intPtr(600),
+		},
+		// the server TTL applies, so the client TTL is unset again when the watch ends
+		"no client ttl": {
+			serverSettings:     &db_common.ServerSettings{CacheMaxTtl: 300},
+			expectedWatchTtl:   intPtr(10),
+			expectedRestoreTtl: nil,
+		},
+		"no client or server ttl": {
+			expectedWatchTtl:   nil,
+			expectedRestoreTtl: nil,
+		},
+	}
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			defer viper.Reset()
+			if test.clientTtl != nil {
+				viper.Set(constants.ArgCacheTtl, *test.clientTtl)
+			}
+
+			restore := overrideCacheTtl(test.serverSettings, 10*time.Second)
+			assertCacheTtl(t, "watch", test.expectedWatchTtl)
+			restore()
+			assertCacheTtl(t, "restore", test.expectedRestoreTtl)
+		})
+	}
+}
+
+func assertCacheTtl(t *testing.T, stage string, expected *int) {
+	t.Helper()
+	if expected == nil {
+		if viper.IsSet(constants.ArgCacheTtl) {
+			t.Errorf("%s: expected the cache ttl to be unset, got %v", stage, viper.Get(constants.ArgCacheTtl))
+		}
+		return
+	}
+	if got := viper.GetInt(constants.ArgCacheTtl); !viper.IsSet(constants.ArgCacheTtl) || got != *expected {
+		t.Errorf("%s: expected cache ttl %d, got %v", stage, *expected, viper.Get(constants.ArgCacheTtl))
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -42,6 +42,13 @@ func setTiming(_ context.Context, input *HandlerInput) error {
 	return nil
 }
 
+// .explain
+// set the ArgExplain viper key with the boolean value evaluated from arg[0]
+func setExplain(_ context.Context, input *HandlerInput) error {
+	cmdconfig.Viper().Set(constants.ArgExplain, typeHelpers.StringToBool(input.args()[0]))
+	return nil
+}
+
 // .separator and .output
 // set the value of `viperKey` in `viper` with the value from `args[0]`
 func setViperConfigFromArg(viperKey string) handler {
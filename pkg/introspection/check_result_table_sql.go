@@ -0,0 +1,155 @@
+package introspection
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+// GetCheckResultTablesCreateSql returns the sql to create the tables which store the history of check runs
+// NOTE: unlike the other internal tables, these are never dropped, as they hold the history of all runs
+func GetCheckResultTablesCreateSql() []db_common.QueryWithArgs {
+	return []db_common.QueryWithArgs{
+		{
+			Query: fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s (
+	run_id TEXT PRIMARY KEY,
+	target TEXT,
+	mod_name TEXT,
+	start_time TIMESTAMPTZ,
+	end_time TIMESTAMPTZ,
+	ok INTEGER,
+	alarm INTEGER,
+	info INTEGER,
+	skip INTEGER,
+	error INTEGER,
+	suppressed INTEGER
+);`, constants.InternalSchema, constants.CheckRunTable),
+		},
+		{
+			Query: fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.%s (
+	run_id TEXT REFERENCES %s.%s (run_id) ON DELETE CASCADE,
+	start_time TIMESTAMPTZ,
+	control_name TEXT,
+	control_title TEXT,
+	benchmark_name TEXT,
+	severity TEXT,
+	resource TEXT,
+	status TEXT,
+	reason TEXT,
+	dimensions JSONB
+);`, constants.InternalSchema, constants.CheckResultTable, constants.InternalSchema, constants.CheckRunTable),
+		},
+		{
+			Query: fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_run_id_idx ON %s.%s (run_id);`,
+				constants.CheckResultTable, constants.InternalSchema, constants.CheckResultTable),
+		},
+		{
+			Query: fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_control_name_idx ON %s.%s (control_name, start_time);`,
+				constants.CheckResultTable, constants.InternalSchema, constants.CheckResultTable),
+		},
+	}
+}
+
+// GetCheckResultTablesGrantSql returns the sql to setup SELECT permission for the 'steampipe_users' role
+// NOTE: results are only written by 'steampipe check', which connects as the root user to store them -
+// database users can not write to the history
+func GetCheckResultTablesGrantSql() []db_common.QueryWithArgs {
+	var res []db_common.QueryWithArgs
+	for _, table := range []string{constants.CheckRunTable, constants.CheckResultTable} {
+		// revoke any other privileges granted by earlier versions
+		res = append(res, db_common.QueryWithArgs{
+			Query: fmt.Sprintf(`REVOKE ALL ON TABLE %s.%s FROM %s;`,
+				constants.InternalSchema, table, constants.DatabaseUsersRole),
+		}, db_common.QueryWithArgs{
+			Query: fmt.Sprintf(`GRANT SELECT ON TABLE %s.%s TO %s;`,
+				constants.InternalSchema, table, constants.DatabaseUsersRole),
+		})
+	}
+	return res
+}
+
+// GetCheckRunDeleteSql returns the sql to delete the check runs started before the given time
+// (the results of the runs are deleted by the cascading foreign key)
+func GetCheckRunDeleteSql(before time.Time) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`DELETE FROM %s.%s WHERE start_time < $1`, constants.InternalSchema, constants.CheckRunTable),
+		Args:  []any{before},
+	}
+}
+
+func GetCheckRunInsertSql(runId, target, modName string, startTime, endTime time.Time, summary *controlstatus.StatusSummary) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`INSERT INTO %s.%s (
+run_id,
+target,
+mod_name,
+start_time,
+end_time,
+ok,
+alarm,
+info,
+skip,
+error,
+suppressed
+)
+	VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`, constants.InternalSchema, constants.CheckRunTable),
+		Args: []any{
+			runId,
+			target,
+			modName,
+			startTime,
+			endTime,
+			summary.Ok,
+			summary.Alarm,
+			summary.Info,
+			summary.Skip,
+			summary.Error,
+			summary.Suppressed,
+		},
+	}
+}
+
+// CheckResult is a single control result row to be stored in the check result table
+type CheckResult struct {
+	ControlName   string
+	ControlTitle  string
+	BenchmarkName string
+	Severity      string
+	Resource      string
+	Status        string
+	Reason        string
+	Dimensions    map[string]string
+}
+
+func GetCheckResultInsertSql(runId string, startTime time.Time, result *CheckResult) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`INSERT INTO %s.%s (
+run_id,
+start_time,
+control_name,
+control_title,
+benchmark_name,
+severity,
+resource,
+status,
+reason,
+dimensions
+)
+	VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`, constants.InternalSchema, constants.CheckResultTable),
+		Args: []any{
+			runId,
+			startTime,
+			result.ControlName,
+			result.ControlTitle,
+			result.BenchmarkName,
+			result.Severity,
+			result.Resource,
+			result.Status,
+			result.Reason,
+			result.Dimensions,
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package introspection
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controlstatus"
+)
+
+func TestGetCheckResultTablesGrantSql(t *testing.T) {
+	// database users may read the history, but only steampipe may write it
+	for _, q := range GetCheckResultTablesGrantSql() {
+		if strings.HasPrefix(q.Query, "GRANT") && !strings.HasPrefix(q.Query, "GRANT SELECT ON") {
+			t.Errorf("expected only SELECT to be granted, got: %s", q.Query)
+		}
+	}
+}
+
+func TestGetCheckRunInsertSql(t *testing.T) {
+	startTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	summary := &controlstatus.StatusSummary{Ok: 1, Alarm: 2, Info: 3, Skip: 4, Error: 5}
+	q := GetCheckRunInsertSql("run1", "benchmark.b1", "mod", startTime, startTime.Add(time.Minute), summary)
+
+	if !strings.Contains(q.Query, constants.InternalSchema+"."+constants.CheckRunTable) {
+		t.Errorf("expected insert into %s, got: %s", constants.CheckRunTable, q.Query)
+	}
+	// there must be an arg for each placeholder
+	if !strings.Contains(q.Query, "$11") || strings.Contains(q.Query, "$12") || len(q.Args) != 11 {
+		t.Errorf("expected 11 args, got %d", len(q.Args))
+	}
+	if q.Args[0] != "run1" || q.Args[6] != 2 || q.Args[9] != 5 {
+		t.Errorf("unexpected args: %v", q.Args)
+	}
+}
+
+func TestGetCheckResultInsertSql(t *testing.T) {
+	result := &CheckResult{ControlName: "mod.control.c1", Resource: "a", Status: constants.ControlAlarm, Dimensions: map[string]string{"region": "us-east-1"}}
+	q := GetCheckResultInsertSql("run1", time.Now(), result)
+
+	if !strings.Contains(q.Query, "$10") || strings.Contains(q.Query, "$11") || len(q.Args) != 10 {
+		t.Errorf("expected 10 args, got %d", len(q.Args))
+	}
+	if q.Args[2] != "mod.control.c1" || q.Args[6] != "a" || q.Args[7] != constants.ControlAlarm {
+		t.Errorf("unexpected args: %v", q.Args)
+	}
+}
@@ -0,0 +1,76 @@
+package introspection
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+)
+
+// GetQueryMetricsTableCreateSql returns the sql to create the table which steampipe clients record their queries in
+// NOTE: the table is unlogged - the records are only held until the plugin manager consumes them,
+// so they do not need to survive a crash
+func GetQueryMetricsTableCreateSql() db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`CREATE UNLOGGED TABLE IF NOT EXISTS %s.%s (
+	duration DOUBLE PRECISION,
+	scans BIGINT,
+	cache_hits BIGINT,
+	rows_fetched BIGINT,
+	cached_rows_fetched BIGINT,
+	hydrate_calls BIGINT
+);`, constants.InternalSchema, constants.QueryMetricsTable),
+	}
+}
+
+// GetQueryMetricsTableGrantSql returns the sql to allow steampipe clients to record their queries
+// NOTE: INSERT is only granted to the steampipe user, which steampipe clients connect as -
+// other database users can not record queries
+func GetQueryMetricsTableGrantSql() []db_common.QueryWithArgs {
+	return []db_common.QueryWithArgs{
+		{
+			Query: fmt.Sprintf(`REVOKE ALL ON TABLE %s.%s FROM %s;`,
+				constants.InternalSchema, constants.QueryMetricsTable, constants.DatabaseUsersRole),
+		},
+		{
+			Query: fmt.Sprintf(`GRANT INSERT ON TABLE %s.%s TO %s;`,
+				constants.InternalSchema, constants.QueryMetricsTable, constants.DatabaseUser),
+		},
+	}
+}
+
+// GetQueryMetricsInsertSql returns the sql to record a query with the given duration, along with the totals of the
+// foreign table scans it made (the scans after scanMetadataMaxId)
+// this is a single statement, so recording a query only costs one round trip
+// the statement returns the max id of the scans, so the caller can move past them
+func GetQueryMetricsInsertSql(duration time.Duration, scanMetadataMaxId int64) db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`WITH scans AS (
+	SELECT id, rows_fetched, cache_hit, hydrate_calls FROM %s.%s WHERE id > %d
+), recorded AS (
+	INSERT INTO %s.%s (duration, scans, cache_hits, rows_fetched, cached_rows_fetched, hydrate_calls)
+	SELECT
+		$1,
+		count(*),
+		count(*) FILTER (WHERE cache_hit),
+		coalesce(sum(rows_fetched) FILTER (WHERE NOT cache_hit), 0),
+		coalesce(sum(rows_fetched) FILTER (WHERE cache_hit), 0),
+		coalesce(sum(hydrate_calls), 0)
+	FROM scans
+)
+SELECT coalesce(max(id), %d) FROM scans`,
+			constants.InternalSchema, constants.ForeignTableScanMetadata, scanMetadataMaxId,
+			constants.InternalSchema, constants.QueryMetricsTable,
+			scanMetadataMaxId),
+		Args: []any{duration.Seconds()},
+	}
+}
+
+// GetQueryMetricsConsumeSql returns the sql to delete and return all recorded queries
+func GetQueryMetricsConsumeSql() db_common.QueryWithArgs {
+	return db_common.QueryWithArgs{
+		Query: fmt.Sprintf(`DELETE FROM %s.%s RETURNING duration, scans, cache_hits, rows_fetched, cached_rows_fetched, hydrate_calls`,
+			constants.InternalSchema, constants.QueryMetricsTable),
+	}
+}
@@ -0,0 +1,33 @@
+package introspection
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/turbot/steampipe/pkg/constants"
+)
+
+func TestGetQueryMetricsTableGrantSql(t *testing.T) {
+	// only the steampipe user, which steampipe clients connect as, may record queries
+	for _, q := range GetQueryMetricsTableGrantSql() {
+		if strings.HasPrefix(q.Query, "GRANT") && !strings.HasSuffix(q.Query, "TO "+constants.DatabaseUser+";") {
+			t.Errorf("expected only the %s user to be granted access, got: %s", constants.DatabaseUser, q.Query)
+		}
+	}
+}
+
+func TestGetQueryMetricsInsertSql(t *testing.T) {
+	q := GetQueryMetricsInsertSql(1500*time.Millisecond, 42)
+
+	// only the scans made after the given id are recorded, and the max id is returned (or the given id if there are none)
+	if !strings.Contains(q.Query, "WHERE id > 42") || !strings.Contains(q.Query, "coalesce(max(id), 42)") {
+		t.Errorf("expected the scans after id 42 to be recorded, got: %s", q.Query)
+	}
+	if !strings.Contains(q.Query, "INSERT INTO "+constants.InternalSchema+"."+constants.QueryMetricsTable) {
+		t.Errorf("expected insert into %s, got: %s", constants.QueryMetricsTable, q.Query)
+	}
+	if len(q.Args) != 1 || q.Args[0] != 1.5 {
+		t.Errorf("expected the duration in seconds as the only arg, got: %v", q.Args)
+	}
+}
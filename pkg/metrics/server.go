@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+)
+
+// Server serves the metrics of a prometheus registry on /metrics
+// (in the OpenMetrics text format, if the scraper requests it)
+type Server struct {
+	listenAddress string
+	srv           *http.Server
+}
+
+func NewServer(listenAddress string, gatherer prometheus.Gatherer) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+		ErrorLog:          errorLogger{},
+	}))
+	return &Server{
+		listenAddress: listenAddress,
+		srv: &http.Server{
+			Addr:              listenAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Start binds the listen address and starts serving in the background
+// binding is done synchronously so that an invalid or in use address is reported to the caller
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.listenAddress)
+	if err != nil {
+		return sperr.WrapWithMessage(err, "failed to start metrics server on %s", s.listenAddress)
+	}
+	log.Printf("[INFO] metrics server listening on %s", listener.Addr())
+
+	go func() {
+		if err := s.srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("[WARN] metrics server stopped: %s", err.Error())
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+// errorLogger writes the errors of the metrics handler to the log
+type errorLogger struct{}
+
+func (errorLogger) Println(v ...interface{}) {
+	log.Printf("[WARN] failed to serve metrics: %s", fmt.Sprint(v...))
+}
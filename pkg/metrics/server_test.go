@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestServerOpenMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	restarts := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_restarts_total", Help: "Number of restarts."}, []string{"plugin"})
+	registry.MustRegister(restarts)
+	restarts.WithLabelValues(`hub.steampipe.io/plugins/turbot/aws@latest`).Inc()
+
+	httpServer := httptest.NewServer(NewServer("localhost:0", registry).srv.Handler)
+	defer httpServer.Close()
+
+	req, err := http.NewRequest(http.MethodGet, httpServer.URL+"/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", res.StatusCode)
+	}
+	if contentType := res.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/openmetrics-text") {
+		t.Errorf("expected OpenMetrics content type, got '%s'", contentType)
+	}
+	for _, expected := range []string{
+		"# TYPE test_restarts counter\n",
+		"# HELP test_restarts Number of restarts.\n",
+		`test_restarts_total{plugin="hub.steampipe.io/plugins/turbot/aws@latest"} 1.0` + "\n",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, body)
+		}
+	}
+	if !strings.HasSuffix(string(body), "# EOF\n") {
+		t.Errorf("expected output to end with '# EOF', got:\n%s", body)
+	}
+
+	// other paths are not served
+	res, err = http.Get(httpServer.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", res.StatusCode)
+	}
+}
@@ -19,7 +19,8 @@ import (
 )
 
 // StartNewInstance loads the plugin manager state, stops any previous instance and instantiates a new plugin manager
-func StartNewInstance(steampipeExecutablePath string) (*State, error) {
+// if metricsListen is set, the plugin manager serves metrics on that address
+func StartNewInstance(steampipeExecutablePath string, metricsListen string) (*State, error) {
 	// try to load the plugin manager state
 	state, err := LoadState()
 	if err != nil {
@@ -35,19 +36,21 @@ func StartNewInstance(steampipeExecutablePath string) (*State, error) {
 			return nil, err
 		}
 	}
-	return start(steampipeExecutablePath)
+	return start(steampipeExecutablePath, metricsListen)
 }
 
 // start plugin manager, without checking it is already running
 // we need to be provided with the exe path as we have no way of knowing where the steampipe exe it
 // when the plugin mananager is first started by steampipe, we derive the exe path from the running process and
 // store it in the plugin manager state file - then if the fdw needs to start the plugin manager it knows how to
-func start(steampipeExecutablePath string) (*State, error) {
+func start(steampipeExecutablePath string, metricsListen string) (*State, error) {
 	// note: we assume the install dir has been assigned to file_paths.SteampipeDir
 	// - this is done both by the FDW and Steampipe
-	pluginManagerCmd := exec.Command(steampipeExecutablePath,
-		"plugin-manager",
-		"--"+constants.ArgInstallDir, filepaths.SteampipeDir)
+	args := []string{"plugin-manager", "--" + constants.ArgInstallDir, filepaths.SteampipeDir}
+	if metricsListen != "" {
+		args = append(args, "--"+constants.ArgMetricsListen, metricsListen)
+	}
+	pluginManagerCmd := exec.Command(steampipeExecutablePath, args...)
 	// set attributes on the command to ensure the process is not shutdown when its parent terminates
 	pluginManagerCmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
@@ -75,6 +78,8 @@ func start(steampipeExecutablePath string) (*State, error) {
 
 	// create a plugin manager state.
 	state := NewState(steampipeExecutablePath, client.ReattachConfig())
+	// store the metrics listen address so that it is retained if the plugin manager is restarted by the FDW
+	state.MetricsListen = metricsListen
 
 	log.Printf("[TRACE] start: started plugin manager, pid %d", state.Pid)
 
@@ -160,7 +165,7 @@ func getPluginManager(startIfNeeded bool) (pluginshared.PluginManager, error) {
 	if startIfNeeded {
 		log.Printf("[TRACE] calling StartNewInstance()")
 		// start the plugin manager
-		if _, err := start(state.Executable); err != nil {
+		if _, err := start(state.Executable, state.MetricsListen); err != nil {
 			return nil, err
 		}
 		// recurse in, setting startIfNeeded to false to avoid further recursion on failure
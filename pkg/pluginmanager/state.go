@@ -22,6 +22,8 @@ type State struct {
 	Pid             int             `json:"pid"`
 	// path to the steampipe executable
 	Executable string `json:"executable"`
+	// the address the plugin manager serves metrics on - empty if metrics are not enabled
+	MetricsListen string `json:"metrics_listen,omitempty"`
 	// is the plugin manager running
 	Running       bool  `json:"-"`
 	StructVersion int64 `json:"struct_version"`
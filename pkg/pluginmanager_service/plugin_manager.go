@@ -31,6 +31,7 @@ import (
 	"github.com/turbot/steampipe/pkg/pluginmanager_service/grpc"
 	pb "github.com/turbot/steampipe/pkg/pluginmanager_service/grpc/proto"
 	pluginshared "github.com/turbot/steampipe/pkg/pluginmanager_service/grpc/shared"
+	"github.com/turbot/steampipe/pkg/scheduler"
 	"github.com/turbot/steampipe/pkg/steampipeconfig"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/utils"
@@ -75,6 +76,11 @@ type PluginManager struct {
 	plugins connection.PluginMap
 
 	pool *pgxpool.Pool
+
+	metrics *pluginManagerMetrics
+
+	// runs the benchmarks defined by schedule config blocks
+	scheduler *scheduler.Scheduler
 }
 
 func NewPluginManager(ctx context.Context, connectionConfig map[string]*sdkproto.ConnectionConfig, pluginConfigs connection.PluginMap, logger hclog.Logger) (*PluginManager, error) {
@@ -85,6 +91,7 @@ func NewPluginManager(ctx context.Context, connectionConfig map[string]*sdkproto
 		connectionConfigMap: connectionConfig,
 		userLimiters:        pluginConfigs.ToPluginLimiterMap(),
 		plugins:             pluginConfigs,
+		metrics:             newPluginManagerMetrics(),
 	}
 
 	pluginManager.messageServer = &PluginMessageServer{pluginManager: pluginManager}
@@ -254,6 +261,9 @@ func (m *PluginManager) Shutdown(*pb.ShutdownRequest) (resp *pb.ShutdownResponse
 	m.shutdownMut.Lock()
 	m.startPluginWg.Wait()
 
+	m.stopMetricsServer()
+	m.stopScheduler()
+
 	// close our pool
 	log.Printf("[INFO] PluginManager closing pool")
 	m.pool.Close()
@@ -421,6 +431,8 @@ func (m *PluginManager) startPlugin(pluginInstance string, connectionConfigs []*
 	// close initialized chan to advertise that this plugin is ready
 	close(startingPlugin.initialized)
 
+	m.metrics.recordPluginStarted(pluginInstance, startingPlugin.imageRef)
+
 	log.Printf("[INFO] PluginManager ensurePlugin complete, returning reattach config with PID: %d (%p)", reattach.Pid, req)
 
 	// and return
@@ -0,0 +1,320 @@
+package pluginmanager_service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	psutils "github.com/shirou/gopsutil/process"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/introspection"
+	"github.com/turbot/steampipe/pkg/metrics"
+)
+
+var (
+	pluginMemoryDesc = prometheus.NewDesc("steampipe_plugin_memory_bytes", "Resident memory of each running plugin process.", []string{"plugin_instance", "plugin", "pid"}, nil)
+	connectionsDesc  = prometheus.NewDesc("steampipe_connections", "Number of connections in each state.", []string{"state"}, nil)
+	// database metrics are read from the database statistics, so they include the queries of every client
+	// (including clients other than steampipe)
+	sessionsDesc     = prometheus.NewDesc("steampipe_database_sessions", "Number of sessions connected to the steampipe database.", nil, nil)
+	transactionsDesc = prometheus.NewDesc("steampipe_database_transactions_total", "Number of transactions executed in the steampipe database, by whether they were committed or rolled back.", []string{"result"}, nil)
+	activeTimeDesc   = prometheus.NewDesc("steampipe_database_active_seconds_total", "Time spent executing queries in the steampipe database.", nil, nil)
+)
+
+// queryDurationBuckets are the upper bounds (in seconds) of the buckets used for query durations
+var queryDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// metricsDb is the subset of the connection pool used to read metrics
+type metricsDb interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// pluginManagerMetrics holds the metrics which are accumulated by the plugin manager
+// (point in time values such as plugin memory and connection state are read when metrics are collected)
+type pluginManagerMetrics struct {
+	server   *metrics.Server
+	registry *prometheus.Registry
+
+	pluginRestarts *prometheus.CounterVec
+
+	// set of plugin instances which have been started, used to identify restarts
+	startedPlugins map[string]struct{}
+	mut            sync.Mutex
+}
+
+func newPluginManagerMetrics() *pluginManagerMetrics {
+	p := &pluginManagerMetrics{
+		registry: prometheus.NewRegistry(),
+		pluginRestarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "steampipe_plugin_restarts_total",
+			Help: "Number of times a plugin instance has been restarted after its process exited.",
+		}, []string{"plugin_instance", "plugin"}),
+		startedPlugins: make(map[string]struct{}),
+	}
+	p.registry.MustRegister(p.pluginRestarts)
+	return p
+}
+
+// StartMetricsServer starts serving metrics on the given address
+func (m *PluginManager) StartMetricsServer(ctx context.Context, listenAddress string) error {
+	log.Printf("[INFO] PluginManager StartMetricsServer %s", listenAddress)
+
+	// create the table steampipe clients record their queries in
+	if err := m.createQueryMetricsTable(ctx); err != nil {
+		return err
+	}
+	if err := m.metrics.registry.Register(&pluginManagerCollector{pluginManager: m, db: m.pool}); err != nil {
+		return err
+	}
+	if err := m.metrics.registry.Register(newQueryMetricsCollector(m.pool)); err != nil {
+		return err
+	}
+	server := metrics.NewServer(listenAddress, m.metrics.registry)
+	if err := server.Start(); err != nil {
+		return err
+	}
+	m.metrics.server = server
+	return nil
+}
+
+func (m *PluginManager) createQueryMetricsTable(ctx context.Context) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	queries := append([]db_common.QueryWithArgs{introspection.GetQueryMetricsTableCreateSql()}, introspection.GetQueryMetricsTableGrantSql()...)
+	_, err = db_local.ExecuteSqlWithArgsInTransaction(ctx, conn.Conn(), queries...)
+	return err
+}
+
+func (m *PluginManager) stopMetricsServer() {
+	if m.metrics.server == nil {
+		return
+	}
+	log.Printf("[INFO] PluginManager stopping metrics server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.metrics.server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("[WARN] metrics server shutdown failed: %s", err.Error())
+	}
+}
+
+// recordPluginStarted is called whenever a plugin instance is started
+// as plugins are only stopped when the plugin manager shuts down, any start after the first is a restart
+func (p *pluginManagerMetrics) recordPluginStarted(pluginInstance, imageRef string) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	if _, started := p.startedPlugins[pluginInstance]; started {
+		p.pluginRestarts.WithLabelValues(pluginInstance, imageRef).Inc()
+		return
+	}
+	p.startedPlugins[pluginInstance] = struct{}{}
+}
+
+// pluginManagerCollector reads the point in time metrics of the plugin manager and the database for every scrape
+// failing to read the database metrics is logged, but does not fail the scrape
+type pluginManagerCollector struct {
+	pluginManager *PluginManager
+	db            metricsDb
+}
+
+// Describe implements prometheus.Collector
+func (c *pluginManagerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pluginMemoryDesc
+	ch <- connectionsDesc
+	ch <- sessionsDesc
+	ch <- transactionsDesc
+	ch <- activeTimeDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *pluginManagerCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectPluginMemory(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.collectConnectionStates(ctx, ch); err != nil {
+		log.Printf("[WARN] failed to read connection states for metrics: %s", err.Error())
+	}
+	if err := c.collectDatabaseStats(ctx, ch); err != nil {
+		log.Printf("[WARN] failed to read database statistics for metrics: %s", err.Error())
+	}
+}
+
+// collectPluginMemory reports the resident memory of each running plugin process
+func (c *pluginManagerCollector) collectPluginMemory(ch chan<- prometheus.Metric) {
+	m := c.pluginManager
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	for pluginInstance, p := range m.runningPluginMap {
+		// skip plugins which are still starting
+		if p.reattach == nil {
+			continue
+		}
+		process, err := psutils.NewProcess(int32(p.reattach.Pid))
+		if err != nil {
+			continue
+		}
+		memoryInfo, err := process.MemoryInfo()
+		if err != nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(pluginMemoryDesc, prometheus.GaugeValue, float64(memoryInfo.RSS), pluginInstance, p.imageRef, strconv.FormatInt(p.reattach.Pid, 10))
+	}
+}
+
+// collectConnectionStates reports the number of connections in each state, read from the connection state table
+func (c *pluginManagerCollector) collectConnectionStates(ctx context.Context, ch chan<- prometheus.Metric) error {
+	query := fmt.Sprintf("select state, count(*) from %s.%s group by state", constants.InternalSchema, constants.ConnectionTable)
+	rows, err := c.db.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var state string
+		var count int64
+		if err := rows.Scan(&state, &count); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(connectionsDesc, prometheus.GaugeValue, float64(count), state)
+	}
+	return rows.Err()
+}
+
+// collectDatabaseStats reports the session, transaction and query time statistics of the steampipe database
+func (c *pluginManagerCollector) collectDatabaseStats(ctx context.Context, ch chan<- prometheus.Metric) error {
+	var sessions, commits, rollbacks int64
+	// active_time is in milliseconds
+	var activeTime float64
+	row := c.db.QueryRow(ctx, "select numbackends, xact_commit, xact_rollback, active_time from pg_stat_database where datname = current_database()")
+	if err := row.Scan(&sessions, &commits, &rollbacks, &activeTime); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(sessionsDesc, prometheus.GaugeValue, float64(sessions))
+	ch <- prometheus.MustNewConstMetric(transactionsDesc, prometheus.CounterValue, float64(commits), "commit")
+	ch <- prometheus.MustNewConstMetric(transactionsDesc, prometheus.CounterValue, float64(rollbacks), "rollback")
+	ch <- prometheus.MustNewConstMetric(activeTimeDesc, prometheus.CounterValue, activeTime/1000)
+	return nil
+}
+
+// queryMetricsCollector reports the metrics of the queries executed by steampipe clients
+// clients record each query in the query metrics table - for every scrape, the collector consumes the new records
+// and adds them to its metrics, so the metrics accumulate for the life of the plugin manager
+type queryMetricsCollector struct {
+	db metricsDb
+
+	queries       prometheus.Counter
+	queryDuration prometheus.Histogram
+	scans         prometheus.Counter
+	scanCacheHits prometheus.Counter
+	rowsFetched   *prometheus.CounterVec
+	hydrateCalls  prometheus.Counter
+	// protects the metrics while records are consumed, as scrapes may be concurrent
+	mut sync.Mutex
+}
+
+func newQueryMetricsCollector(db metricsDb) *queryMetricsCollector {
+	c := &queryMetricsCollector{
+		db: db,
+		queries: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "steampipe_queries_total",
+			Help: "Number of queries executed by steampipe clients.",
+		}),
+		queryDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "steampipe_query_duration_seconds",
+			Help:    "Duration of the queries executed by steampipe clients.",
+			Buckets: queryDurationBuckets,
+		}),
+		scans: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "steampipe_scans_total",
+			Help: "Number of foreign table scans made by the queries of steampipe clients.",
+		}),
+		scanCacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "steampipe_scan_cache_hits_total",
+			Help: "Number of foreign table scans which were served from the plugin cache.",
+		}),
+		rowsFetched: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "steampipe_rows_fetched_total",
+			Help: "Number of rows fetched by foreign table scans, by whether they were served from the plugin cache.",
+		}, []string{"cached"}),
+		hydrateCalls: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "steampipe_hydrate_calls_total",
+			Help: "Number of hydrate calls made by foreign table scans.",
+		}),
+	}
+	// report rows fetched from both sources, even before any have been fetched
+	c.rowsFetched.WithLabelValues("false")
+	c.rowsFetched.WithLabelValues("true")
+	return c
+}
+
+// Describe implements prometheus.Collector
+func (c *queryMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.queries.Describe(ch)
+	c.queryDuration.Describe(ch)
+	c.scans.Describe(ch)
+	c.scanCacheHits.Describe(ch)
+	c.rowsFetched.Describe(ch)
+	c.hydrateCalls.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *queryMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// failing to read the records is logged, and the metrics collected so far are still reported
+	// (the records are not lost - they are deleted in the same statement that returns them)
+	if err := c.consumeRecords(ctx); err != nil {
+		log.Printf("[WARN] failed to read query metrics: %s", err.Error())
+	}
+
+	c.queries.Collect(ch)
+	c.queryDuration.Collect(ch)
+	c.scans.Collect(ch)
+	c.scanCacheHits.Collect(ch)
+	c.rowsFetched.Collect(ch)
+	c.hydrateCalls.Collect(ch)
+}
+
+// consumeRecords deletes the queries recorded since the last scrape and adds them to the metrics
+func (c *queryMetricsCollector) consumeRecords(ctx context.Context) error {
+	consume := introspection.GetQueryMetricsConsumeSql()
+	rows, err := c.db.Query(ctx, consume.Query, consume.Args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var duration float64
+		var scans, cacheHits, rowsFetched, cachedRowsFetched, hydrateCalls int64
+		if err := rows.Scan(&duration, &scans, &cacheHits, &rowsFetched, &cachedRowsFetched, &hydrateCalls); err != nil {
+			return err
+		}
+		c.queries.Inc()
+		c.queryDuration.Observe(duration)
+		c.scans.Add(float64(scans))
+		c.scanCacheHits.Add(float64(cacheHits))
+		c.rowsFetched.WithLabelValues("false").Add(float64(rowsFetched))
+		c.rowsFetched.WithLabelValues("true").Add(float64(cachedRowsFetched))
+		c.hydrateCalls.Add(float64(hydrateCalls))
+	}
+	return rows.Err()
+}
@@ -0,0 +1,182 @@
+package pluginmanager_service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/turbot/steampipe/pkg/constants"
+	pb "github.com/turbot/steampipe/pkg/pluginmanager_service/grpc/proto"
+)
+
+// testRows is a pgx.Rows which returns the given values
+type testRows struct {
+	pgx.Rows
+	values [][]any
+	index  int
+}
+
+func (r *testRows) Next() bool {
+	r.index++
+	return r.index <= len(r.values)
+}
+
+func (r *testRows) Scan(dest ...any) error {
+	for i, v := range r.values[r.index-1] {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+	}
+	return nil
+}
+
+func (r *testRows) Err() error { return nil }
+
+func (r *testRows) Close() {}
+
+// testMetricsDb returns the rows of the table named in the query
+// the query metrics records are returned once, as they are deleted when they are read
+type testMetricsDb struct {
+	tables       map[string][][]any
+	queryMetrics [][]any
+	scrapes      int
+}
+
+func (d *testMetricsDb) Query(_ context.Context, sql string, _ ...any) (pgx.Rows, error) {
+	// the query metrics records are deleted as they are read
+	if strings.Contains(sql, constants.QueryMetricsTable) {
+		d.scrapes++
+		rows := &testRows{values: d.queryMetrics}
+		d.queryMetrics = nil
+		return rows, nil
+	}
+	for table, values := range d.tables {
+		if strings.Contains(sql, table) {
+			return &testRows{values: values}, nil
+		}
+	}
+	return nil, errors.New("unexpected query: " + sql)
+}
+
+func (d *testMetricsDb) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, _ := d.Query(ctx, sql, args...)
+	rows.Next()
+	return rows
+}
+
+func TestPluginManagerCollector(t *testing.T) {
+	pluginManager := &PluginManager{
+		runningPluginMap: map[string]*runningPlugin{
+			// use the test process as the plugin process, so it has some memory
+			"aws": {imageRef: "hub.steampipe.io/plugins/turbot/aws@latest", reattach: &pb.ReattachConfig{Pid: int64(os.Getpid())}},
+			// a plugin which is still starting has no process yet, so is not reported
+			"gcp": {imageRef: "hub.steampipe.io/plugins/turbot/gcp@latest"},
+		},
+	}
+	db := &testMetricsDb{
+		tables: map[string][][]any{
+			constants.ConnectionTable: {{"ready", int64(2)}, {"error", int64(1)}},
+			"pg_stat_database":        {{int64(3), int64(10), int64(2), float64(1500)}},
+		},
+	}
+	collector := &pluginManagerCollector{pluginManager: pluginManager, db: db}
+
+	if count := testutil.CollectAndCount(collector, "steampipe_plugin_memory_bytes"); count != 1 {
+		t.Errorf("expected memory to be reported for 1 plugin, got %d", count)
+	}
+
+	expected := `
+# HELP steampipe_connections Number of connections in each state.
+# TYPE steampipe_connections gauge
+steampipe_connections{state="error"} 1
+steampipe_connections{state="ready"} 2
+# HELP steampipe_database_active_seconds_total Time spent executing queries in the steampipe database.
+# TYPE steampipe_database_active_seconds_total counter
+steampipe_database_active_seconds_total 1.5
+# HELP steampipe_database_sessions Number of sessions connected to the steampipe database.
+# TYPE steampipe_database_sessions gauge
+steampipe_database_sessions 3
+# HELP steampipe_database_transactions_total Number of transactions executed in the steampipe database, by whether they were committed or rolled back.
+# TYPE steampipe_database_transactions_total counter
+steampipe_database_transactions_total{result="commit"} 10
+steampipe_database_transactions_total{result="rollback"} 2
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"steampipe_connections", "steampipe_database_active_seconds_total", "steampipe_database_sessions", "steampipe_database_transactions_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQueryMetricsCollector(t *testing.T) {
+	db := &testMetricsDb{
+		// duration, scans, cache hits, rows fetched, cached rows fetched, hydrate calls
+		queryMetrics: [][]any{
+			{0.2, int64(2), int64(1), int64(10), int64(5), int64(20)},
+			// a query which made no scans
+			{0.01, int64(0), int64(0), int64(0), int64(0), int64(0)},
+		},
+	}
+	collector := newQueryMetricsCollector(db)
+
+	expectedCounters := func(queries, scans, cacheHits, rowsFetched, cachedRowsFetched, hydrateCalls string) string {
+		return `
+# HELP steampipe_hydrate_calls_total Number of hydrate calls made by foreign table scans.
+# TYPE steampipe_hydrate_calls_total counter
+steampipe_hydrate_calls_total ` + hydrateCalls + `
+# HELP steampipe_queries_total Number of queries executed by steampipe clients.
+# TYPE steampipe_queries_total counter
+steampipe_queries_total ` + queries + `
+# HELP steampipe_rows_fetched_total Number of rows fetched by foreign table scans, by whether they were served from the plugin cache.
+# TYPE steampipe_rows_fetched_total counter
+steampipe_rows_fetched_total{cached="false"} ` + rowsFetched + `
+steampipe_rows_fetched_total{cached="true"} ` + cachedRowsFetched + `
+# HELP steampipe_scan_cache_hits_total Number of foreign table scans which were served from the plugin cache.
+# TYPE steampipe_scan_cache_hits_total counter
+steampipe_scan_cache_hits_total ` + cacheHits + `
+# HELP steampipe_scans_total Number of foreign table scans made by the queries of steampipe clients.
+# TYPE steampipe_scans_total counter
+steampipe_scans_total ` + scans + `
+`
+	}
+	counterNames := []string{"steampipe_hydrate_calls_total", "steampipe_queries_total", "steampipe_rows_fetched_total", "steampipe_scan_cache_hits_total", "steampipe_scans_total"}
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expectedCounters("2", "2", "1", "10", "5", "20")), counterNames...); err != nil {
+		t.Error(err)
+	}
+
+	// the records have been consumed, so a scrape with no new records reports the same values
+	// and new records are added to them
+	db.queryMetrics = [][]any{{3.0, int64(1), int64(0), int64(7), int64(0), int64(1)}}
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expectedCounters("3", "3", "1", "17", "5", "21")), counterNames...); err != nil {
+		t.Error(err)
+	}
+
+	expectedDuration := `
+# HELP steampipe_query_duration_seconds Duration of the queries executed by steampipe clients.
+# TYPE steampipe_query_duration_seconds histogram
+steampipe_query_duration_seconds_bucket{le="0.05"} 1
+steampipe_query_duration_seconds_bucket{le="0.1"} 1
+steampipe_query_duration_seconds_bucket{le="0.25"} 2
+steampipe_query_duration_seconds_bucket{le="0.5"} 2
+steampipe_query_duration_seconds_bucket{le="1"} 2
+steampipe_query_duration_seconds_bucket{le="2.5"} 2
+steampipe_query_duration_seconds_bucket{le="5"} 3
+steampipe_query_duration_seconds_bucket{le="10"} 3
+steampipe_query_duration_seconds_bucket{le="30"} 3
+steampipe_query_duration_seconds_bucket{le="60"} 3
+steampipe_query_duration_seconds_bucket{le="120"} 3
+steampipe_query_duration_seconds_bucket{le="300"} 3
+steampipe_query_duration_seconds_bucket{le="+Inf"} 3
+steampipe_query_duration_seconds_sum 3.21
+steampipe_query_duration_seconds_count 3
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expectedDuration), "steampipe_query_duration_seconds"); err != nil {
+		t.Error(err)
+	}
+	if db.scrapes != 3 {
+		t.Errorf("expected the records to be read for each of the 3 scrapes, got %d", db.scrapes)
+	}
+}
@@ -0,0 +1,34 @@
+package pluginmanager_service
+
+import (
+	"log"
+
+	"github.com/turbot/steampipe/pkg/scheduler"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/utils"
+)
+
+// StartScheduler starts running the given schedules
+func (m *PluginManager) StartScheduler(schedules map[string]*modconfig.Schedule) error {
+	log.Printf("[INFO] PluginManager StartScheduler (%d %s)", len(schedules), utils.Pluralize("schedule", len(schedules)))
+
+	m.scheduler = scheduler.NewScheduler()
+	m.scheduler.SetSchedules(schedules)
+	return nil
+}
+
+// OnScheduleConfigChanged is called by the connection watcher when the config has changed
+func (m *PluginManager) OnScheduleConfigChanged(schedules map[string]*modconfig.Schedule) {
+	if m.scheduler == nil {
+		return
+	}
+	m.scheduler.SetSchedules(schedules)
+}
+
+func (m *PluginManager) stopScheduler() {
+	if m.scheduler == nil {
+		return
+	}
+	log.Printf("[INFO] PluginManager stopping scheduler")
+	m.scheduler.Stop()
+}
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/spf13/viper"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/db/db_client"
+	"github.com/turbot/steampipe/pkg/display"
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/export"
 	"github.com/turbot/steampipe/pkg/initialisation"
@@ -54,7 +56,7 @@ func NewInitData(ctx context.Context, args []string) *InitData {
 }
 
 func queryExporters() []export.Exporter {
-	return []export.Exporter{&export.SnapshotExporter{}}
+	return append([]export.Exporter{&export.SnapshotExporter{}}, display.QueryResultExporters()...)
 }
 
 func (i *InitData) Cancel() {
@@ -91,10 +93,13 @@ func (i *InitData) init(ctx context.Context, args []string) {
 		i.cancelInitialisation = nil
 	}()
 
-	// validate export args
-	if len(viper.GetStringSlice(constants.ArgExport)) > 0 {
+	// the exporters are always needed for interactive sessions, as results may be exported with .export
+	if viper.GetBool(constants.ConfigKeyInteractive) || len(viper.GetStringSlice(constants.ArgExport)) > 0 {
 		i.RegisterExporters(queryExporters()...)
+	}
 
+	// validate export args
+	if len(viper.GetStringSlice(constants.ArgExport)) > 0 {
 		// validate required export formats
 		if err := i.ExportManager.ValidateExportFormat(viper.GetStringSlice(constants.ArgExport)); err != nil {
 			i.Result.Error = err
@@ -111,8 +116,13 @@ func (i *InitData) init(ctx context.Context, args []string) {
 	i.Result.AddWarnings(errAndWarnings.Warnings...)
 	i.Workspace = w
 
-	// set max DB connections to 1
-	viper.Set(constants.ArgMaxParallel, 1)
+	// the interactive prompt uses a single DB connection
+	// batch queries may be run concurrently, each in its own session, so use up to max-parallel connections
+	maxParallel := 1
+	if !viper.GetBool(constants.ConfigKeyInteractive) {
+		maxParallel = max(viper.GetInt(constants.ArgMaxParallel), 1)
+	}
+	viper.Set(constants.ArgMaxParallel, maxParallel)
 
 	statushooks.SetStatus(ctx, "Resolving arguments")
 
@@ -128,12 +138,18 @@ func (i *InitData) init(ctx context.Context, args []string) {
 	i.cancelInitialisation = cancel
 	i.Queries = resolvedQueries
 
+	// a named export target (e.g. --export=result.csv) can only hold the result of a single query
+	if len(resolvedQueries) > 1 && i.ExportManager.HasNamedExport(viper.GetStringSlice(constants.ArgExport)) {
+		i.Result.Error = sperr.New("cannot export the results of multiple queries to a named file - use the export format name instead (e.g. --export=csv)")
+		return
+	}
+
 	// and call base init
 	i.InitData.Init(
 		ctx,
 		constants.InvokerQuery,
 		db_client.WithUserPoolOverride(db_client.PoolOverrides{
-			Size:        1,
+			Size:        maxParallel,
 			MaxLifeTime: 24 * time.Hour,
 			MaxIdleTime: 24 * time.Hour,
 		}),
@@ -3,6 +3,8 @@ package queryexecute
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/viper"
@@ -15,8 +17,11 @@ import (
 	"github.com/turbot/steampipe/pkg/error_helpers"
 	"github.com/turbot/steampipe/pkg/interactive"
 	"github.com/turbot/steampipe/pkg/query"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
 	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
 	"github.com/turbot/steampipe/pkg/utils"
+	"github.com/turbot/steampipe/pkg/workspace"
+	"golang.org/x/sync/semaphore"
 )
 
 func RunInteractiveSession(ctx context.Context, initData *query.InitData) error {
@@ -73,21 +78,44 @@ func executeQueries(ctx context.Context, initData *query.InitData) int {
 	// failures return the number of queries that failed and also the number of rows that
 	// returned errors
 	failures := 0
-	t := time.Now()
 	// build ordered list of queries
 	// (ordered for testing repeatability)
 	var queryNames = utils.SortedMapKeys(initData.Queries)
-	var err error
+
+	// if max-parallel allows, execute the queries concurrently, each in its own session
+	// (the results are still displayed in the order of the queries)
+	var executions []chan *queryExecution
+	if viper.GetInt(constants.ArgMaxParallel) > 1 && len(queryNames) > 1 {
+		executions = startParallelQueries(ctx, initData, queryNames)
+	}
 
 	for i, name := range queryNames {
 		q := initData.Queries[name]
-		// if executeQuery fails it returns err, else it returns the number of rows that returned errors while execution
-		if err, failures = executeQuery(ctx, initData.Client, q); err != nil {
+		exportName := getExportName(name, i, len(queryNames), initData.Workspace)
+		var err error
+		var rowErrors int
+		var duration time.Duration
+		if executions == nil {
+			t := time.Now()
+			// if executeQuery fails it returns err, else it returns the number of rows that returned errors while execution
+			err, rowErrors = executeQuery(ctx, initData, q, exportName)
+			duration = time.Since(t)
+		} else {
+			execution := <-executions[i]
+			err, duration = execution.err, execution.duration
+			if err == nil {
+				err, rowErrors = showQueryResult(ctx, initData, execution.result.Replay(), exportName)
+			}
+			// now the result has been displayed, allow the next query to start
+			execution.release()
+		}
+		failures += rowErrors
+		if err != nil {
 			failures++
 			error_helpers.ShowWarning(fmt.Sprintf("executeQueries: query %d of %d failed: %v", i+1, len(queryNames), error_helpers.DecodePgError(err)))
 			// if timing flag is enabled, show the time taken for the query to fail
 			if cmdconfig.Viper().GetBool(constants.ArgTiming) {
-				display.DisplayErrorTiming(t)
+				display.DisplayErrorDuration(duration)
 			}
 		}
 		// TODO move into display layer
@@ -100,27 +128,148 @@ func executeQueries(ctx context.Context, initData *query.InitData) int {
 	return failures
 }
 
-func executeQuery(ctx context.Context, client db_common.Client, resolvedQuery *modconfig.ResolvedQuery) (error, int) {
+// queryExecution is the outcome of a query executed by startParallelQueries
+type queryExecution struct {
+	// the result is read in full, so the session is released for the next query while earlier results are displayed
+	result *queryresult.BufferedResult
+	err    error
+	// the time taken to execute the query
+	duration time.Duration
+	// release must be called once the result has been displayed
+	release func()
+}
+
+// startParallelQueries executes up to max-parallel of the queries at once,
+// returning a channel for each query which receives its execution once it is complete
+// a query holds its parallelism slot until its execution has been released, i.e. its result has been displayed,
+// so at most max-parallel results are executing or waiting to be displayed at any time
+// (a slow query therefore limits how far ahead of it the other queries may run)
+func startParallelQueries(ctx context.Context, initData *query.InitData, queryNames []string) []chan *queryExecution {
+	executions := make([]chan *queryExecution, len(queryNames))
+	for i := range executions {
+		executions[i] = make(chan *queryExecution, 1)
+	}
+	parallelismLock := semaphore.NewWeighted(viper.GetInt64(constants.ArgMaxParallel))
+	release := func() { parallelismLock.Release(1) }
+
+	go func() {
+		for i, name := range queryNames {
+			// start the queries in order, so the earliest results are available first
+			// NOTE: this cannot deadlock, as the slots are only held by earlier queries, which are displayed first
+			if err := parallelismLock.Acquire(ctx, 1); err != nil {
+				executions[i] <- &queryExecution{err: err, release: func() {}}
+				continue
+			}
+			go func(resolvedQuery *modconfig.ResolvedQuery, execution chan *queryExecution) {
+				t := time.Now()
+				result, err := initData.Client.Execute(ctx, resolvedQuery.ExecuteSQL, resolvedQuery.Args...)
+				if err != nil {
+					execution <- &queryExecution{err: err, duration: time.Since(t), release: release}
+					return
+				}
+				bufferedResult := queryresult.NewBufferedResult(result)
+				execution <- &queryExecution{result: bufferedResult, duration: time.Since(t), release: release}
+			}(initData.Queries[name], executions[i])
+		}
+	}()
+	return executions
+}
+
+func executeQuery(ctx context.Context, initData *query.InitData, resolvedQuery *modconfig.ResolvedQuery, exportName string) (error, int) {
 	utils.LogTime("query.execute.executeQuery start")
 	defer utils.LogTime("query.execute.executeQuery end")
 
 	// the db executor sends result data over resultsStreamer
-	resultsStreamer, err := db_common.ExecuteQuery(ctx, client, resolvedQuery.ExecuteSQL, resolvedQuery.Args...)
+	resultsStreamer, err := db_common.ExecuteQuery(ctx, initData.Client, resolvedQuery.ExecuteSQL, resolvedQuery.Args...)
 	if err != nil {
 		return err, 0
 	}
 
+	var exportErr error
 	rowErrors := 0 // get the number of rows that returned an error
 	// print the data as it comes
 	for r := range resultsStreamer.Results {
-		rowErrors = display.ShowOutput(ctx, r)
+		exportErr, rowErrors = showQueryResult(ctx, initData, r, exportName)
 		// signal to the resultStreamer that we are done with this result
 		resultsStreamer.AllResultsRead()
 	}
-	return nil, rowErrors
+	return exportErr, rowErrors
 }
 
-// if we are displaying csv with no header, do not include lines between the query results
+// showQueryResult displays the result and writes it to any export targets
+// it returns any export error and the number of rows that returned an error
+func showQueryResult(ctx context.Context, initData *query.InitData, r *queryresult.Result, exportName string) (error, int) {
+	exportArgs := viper.GetStringSlice(constants.ArgExport)
+	if len(exportArgs) == 0 {
+		return nil, display.ShowOutput(ctx, r)
+	}
+
+	// the result must be both displayed and exported - stream the rows to each consumer as they arrive
+	// rather than buffering them, so large results are not held in memory
+	results := queryresult.Tee(r, len(exportArgs)+1)
+	exportComplete := make(chan error, 1)
+	go func() {
+		exportComplete <- exportQueryResult(ctx, initData, exportName, results[1:], exportArgs)
+	}()
+	rowErrors := display.ShowOutput(ctx, results[0])
+	// not all output formats read the result - drain it so the exports are not blocked
+	results[0].Drain()
+	return <-exportComplete, rowErrors
+}
+
+// exportQueryResult writes the result to each of the export targets
+// each export target is given its own result, which is streamed to file concurrently with the others
+func exportQueryResult(ctx context.Context, initData *query.InitData, exportName string, results []*queryresult.Result, exportArgs []string) error {
+	var exportMsg = make([][]string, len(exportArgs))
+	var errors = make([]error, len(exportArgs))
+	var wg sync.WaitGroup
+	for i, exportArg := range exportArgs {
+		wg.Add(1)
+		go func(i int, exportArg string) {
+			defer wg.Done()
+			// the export may fail before reading all rows - drain the result so the other consumers are not blocked
+			defer results[i].Drain()
+			if error_helpers.IsContextCanceled(ctx) {
+				errors[i] = ctx.Err()
+				return
+			}
+			exportMsg[i], errors[i] = initData.ExportManager.DoExport(ctx, exportName, results[i], []string{exportArg})
+		}(i, exportArg)
+	}
+	wg.Wait()
+
+	// print the location where the files are exported if progress=true
+	var locations []string
+	for _, msg := range exportMsg {
+		locations = append(locations, msg...)
+	}
+	if len(locations) > 0 && viper.GetBool(constants.ArgProgress) {
+		fmt.Printf("\n")
+		fmt.Println(strings.Join(locations, "\n"))
+		fmt.Printf("\n")
+	}
+	return error_helpers.CombineErrors(errors...)
+}
+
+// getExportName returns the base name of the export files for a query
+// named queries use the resource name, all other queries use 'query' (suffixed with the query index if there are multiple queries)
+func getExportName(queryName string, queryIdx, queryCount int, w *workspace.Workspace) string {
+	if parsedName, err := modconfig.ParseResourceName(queryName); err == nil {
+		if resource, found := w.GetResource(parsedName); found {
+			return resource.Name()
+		}
+	}
+	if queryCount == 1 {
+		return "query"
+	}
+	return fmt.Sprintf("query_%d", queryIdx+1)
+}
+
+// if we are displaying csv with no header, or jsonl, do not include lines between the query results
 func showBlankLineBetweenResults() bool {
-	return !(viper.GetString(constants.ArgOutput) == "csv" && !viper.GetBool(constants.ArgHeader))
+	output := viper.GetString(constants.ArgOutput)
+	if output == constants.OutputFormatJSONL {
+		return false
+	}
+	return !(output == "csv" && !viper.GetBool(constants.ArgHeader))
 }
@@ -0,0 +1,168 @@
+package queryexecute
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_common"
+	"github.com/turbot/steampipe/pkg/query"
+	"github.com/turbot/steampipe/pkg/query/queryresult"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// testQuery describes how the testClient executes a query
+type testQuery struct {
+	// if set, the query does not return its rows until this is closed
+	wait chan struct{}
+	// if set, the query fails to execute
+	err error
+	// if set, the query returns this row error rather than its row
+	rowErr error
+}
+
+// testClient is a db client which executes the test queries, returning a single row containing the query
+type testClient struct {
+	db_common.Client
+	queries map[string]*testQuery
+
+	// the queries which have been started, in order
+	started []string
+	mut     sync.Mutex
+}
+
+func (c *testClient) Execute(_ context.Context, sql string, _ ...any) (*queryresult.Result, error) {
+	c.mut.Lock()
+	c.started = append(c.started, sql)
+	c.mut.Unlock()
+
+	q := c.queries[sql]
+	if q.err != nil {
+		return nil, q.err
+	}
+	result := queryresult.NewResult([]*queryresult.ColumnDef{{Name: "query", DataType: "TEXT"}})
+	go func() {
+		if q.wait != nil {
+			<-q.wait
+		}
+		if q.rowErr != nil {
+			result.StreamError(q.rowErr)
+		} else {
+			result.StreamRow([]any{sql})
+		}
+		result.Close()
+	}()
+	return result, nil
+}
+
+func (c *testClient) startedQueries() []string {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return append([]string{}, c.started...)
+}
+
+func newTestInitData(client *testClient) *query.InitData {
+	initData := &query.InitData{Queries: make(map[string]*modconfig.ResolvedQuery)}
+	initData.Client = client
+	for sql := range client.queries {
+		initData.Queries[sql] = &modconfig.ResolvedQuery{ExecuteSQL: sql}
+	}
+	return initData
+}
+
+// captureStdout returns everything written to stdout by f
+func captureStdout(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	f()
+	os.Stdout = stdout
+	w.Close()
+	return <-output
+}
+
+func TestExecuteQueriesParallel(t *testing.T) {
+	viper.Set(constants.ArgMaxParallel, 3)
+	viper.Set(constants.ArgOutput, constants.OutputFormatCSV)
+	viper.Set(constants.ArgHeader, false)
+	viper.Set(constants.ArgSeparator, ",")
+	defer viper.Reset()
+
+	// the first query is the slowest, so the later queries complete first
+	slowQuery := make(chan struct{})
+	time.AfterFunc(50*time.Millisecond, func() { close(slowQuery) })
+	client := &testClient{queries: map[string]*testQuery{
+		"q1": {wait: slowQuery},
+		"q2": {},
+		"q3": {err: errors.New("relation does not exist")},
+		"q4": {rowErr: errors.New("rate limit exceeded")},
+		"q5": {},
+	}}
+
+	var failures int
+	output := captureStdout(t, func() {
+		failures = executeQueries(context.Background(), newTestInitData(client))
+	})
+
+	// the results are displayed in the order of the queries, regardless of the order they complete in
+	if expected := "q1\nq2\nq5\n"; output != expected {
+		t.Errorf("expected output:\n%s\ngot:\n%s", expected, output)
+	}
+	// both the failed query and the row error are counted
+	if failures != 2 {
+		t.Errorf("expected 2 failures, got %d", failures)
+	}
+}
+
+func TestStartParallelQueriesLimitsReadAhead(t *testing.T) {
+	viper.Set(constants.ArgMaxParallel, 2)
+	defer viper.Reset()
+
+	slowQuery := make(chan struct{})
+	client := &testClient{queries: map[string]*testQuery{
+		"q1": {wait: slowQuery},
+		"q2": {},
+		"q3": {},
+		"q4": {},
+	}}
+	queryNames := []string{"q1", "q2", "q3", "q4"}
+	executions := startParallelQueries(context.Background(), newTestInitData(client), queryNames)
+
+	// q2 completes, but no more queries are started until the first result has been displayed
+	q2 := <-executions[1]
+	time.Sleep(50 * time.Millisecond)
+	if started := client.startedQueries(); len(started) != 2 {
+		t.Fatalf("expected 2 queries to have started, got %v", started)
+	}
+
+	close(slowQuery)
+	(<-executions[0]).release()
+	<-executions[2]
+	if started := client.startedQueries(); len(started) != 3 || started[2] != "q3" {
+		t.Fatalf("expected q3 to start once q1 was released, got %v", started)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if started := client.startedQueries(); len(started) != 3 {
+		t.Fatalf("expected q4 not to start until q2 was released, got %v", started)
+	}
+
+	q2.release()
+	<-executions[3]
+	if started := client.startedQueries(); len(started) != 4 {
+		t.Errorf("expected all queries to have started, got %v", started)
+	}
+}
@@ -1,107 +1,242 @@
 package queryhistory
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
 	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/turbot/steampipe/pkg/constants"
 	"github.com/turbot/steampipe/pkg/filepaths"
 )
 
+// HistoryEntry is a single query entered in the interactive prompt
+type HistoryEntry struct {
+	Query     string    `json:"query"`
+	Timestamp time.Time `json:"timestamp"`
+	// the workspace (mod location) the query was run in
+	// entries migrated from the legacy history file have no workspace, and are shown in all workspaces
+	Workspace string        `json:"workspace,omitempty"`
+	Duration  time.Duration `json:"duration,omitempty"`
+	RowCount  int           `json:"row_count"`
+	Error     string        `json:"error,omitempty"`
+}
+
 // QueryHistory :: struct for working with history in the interactive mode
+// the history file is shared by all workspaces and all running instances of steampipe,
+// so entries are appended to the file as they are saved, rather than the file being rewritten
 type QueryHistory struct {
-	history []string
+	path      string
+	workspace string
+	// the history entries for this workspace
+	history []*HistoryEntry
 }
 
-// New creates a new QueryHistory object
-func New() (*QueryHistory, error) {
-	history := &QueryHistory{history: []string{}}
-	err := history.load()
-	if err != nil {
+// New creates a new QueryHistory object for the given workspace
+func New(workspace string) (*QueryHistory, error) {
+	internalDir := filepaths.EnsureInternalDir()
+	history := newQueryHistory(filepath.Join(internalDir, constants.HistoryFile), workspace)
+
+	// if there is no history file yet, import the legacy history (if any)
+	if err := history.migrateLegacyHistory(filepath.Join(internalDir, constants.LegacyHistoryFile)); err != nil {
+		log.Printf("[WARN] failed to migrate legacy query history: %s", err.Error())
+	}
+	if err := history.load(); err != nil {
 		return nil, err
 	}
 	return history, nil
 }
 
-// Push adds a string to the history queue trimming to maxHistorySize if necessary
-func (q *QueryHistory) Push(query string) {
+func newQueryHistory(path, workspace string) *QueryHistory {
+	return &QueryHistory{
+		path:      path,
+		workspace: workspace,
+		history:   []*HistoryEntry{},
+	}
+}
+
+// Push adds a query to the history queue
+// it returns the new entry, which must be persisted by calling Save once the query has been run
+// (nil is returned if the query is not added to the history)
+func (q *QueryHistory) Push(query string) *HistoryEntry {
 	if len(strings.TrimSpace(query)) == 0 {
 		// do not store a blank query
-		return
+		return nil
 	}
 
 	// do a strict compare to see if we have this same exact query as the most recent history item
-	if lastElement := q.Peek(); lastElement != nil && (*lastElement) == query {
-		return
+	if lastElement := q.Peek(); lastElement != nil && lastElement.Query == query {
+		return nil
 	}
 
-	// limit the history length to HistorySize
-	historyLength := len(q.history)
-	if historyLength >= constants.HistorySize {
-		q.history = q.history[historyLength-constants.HistorySize+1:]
+	entry := &HistoryEntry{
+		Query:     query,
+		Timestamp: time.Now(),
+		Workspace: q.workspace,
 	}
-
-	// append the new entry
-	q.history = append(q.history, query)
+	// NOTE: the history is not trimmed during a session, so the position of each entry does not change
+	// (it is limited to HistorySize when it is loaded)
+	q.history = append(q.history, entry)
+	return entry
 }
 
 // Peek returns the last element of the history stack.
 // returns nil if there is no history
-func (q *QueryHistory) Peek() *string {
+func (q *QueryHistory) Peek() *HistoryEntry {
 	if len(q.history) == 0 {
 		return nil
 	}
-	return &q.history[len(q.history)-1]
+	return q.history[len(q.history)-1]
 }
 
-// Persist writes the history to the filesystem
-func (q *QueryHistory) Persist() error {
-	var file *os.File
-	var err error
-	defer func() {
-		file.Close()
-	}()
-	path := filepath.Join(filepaths.EnsureInternalDir(), constants.HistoryFile)
-	file, err = os.Create(path)
+// Save appends the entry to the history file
+// each entry is written with a single append-mode write,
+// so concurrent instances of steampipe do not overwrite each other's entries
+func (q *QueryHistory) Save(entry *HistoryEntry) error {
+	data, err := json.Marshal(entry)
 	if err != nil {
 		return err
 	}
+	file, err := os.OpenFile(q.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
 
-	jsonEncoder := json.NewEncoder(file)
-
-	// disable indentation
-	jsonEncoder.SetIndent("", "")
-
-	return jsonEncoder.Encode(q.history)
+	_, err = file.Write(append(data, '\n'))
+	return err
 }
 
-// Get returns the full history
+// Get returns the queries in the history
 func (q *QueryHistory) Get() []string {
+	res := make([]string, len(q.history))
+	for i, entry := range q.history {
+		res[i] = entry.Query
+	}
+	return res
+}
+
+// Entries returns the full history entries
+func (q *QueryHistory) Entries() []*HistoryEntry {
 	return q.history
 }
 
-// loads up the history from the file where it is persisted
+// Search returns the most recent entry before the given index whose query contains the search term
+// along with its index - if no entry matches, the index is -1
+func (q *QueryHistory) Search(term string, before int) (*HistoryEntry, int) {
+	term = strings.ToLower(term)
+	if before > len(q.history) {
+		before = len(q.history)
+	}
+	for i := before - 1; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(q.history[i].Query), term) {
+			return q.history[i], i
+		}
+	}
+	return nil, -1
+}
+
+// loads up the history for this workspace from the file where it is persisted
 func (q *QueryHistory) load() error {
-	path := filepath.Join(filepaths.EnsureInternalDir(), constants.HistoryFile)
-	file, err := os.Open(path)
+	entries, err := readHistoryFile(q.path)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Workspace == "" || entry.Workspace == q.workspace {
+			q.history = append(q.history, entry)
+		}
+	}
+	// limit the history length to HistorySize
+	if historyLength := len(q.history); historyLength > constants.HistorySize {
+		q.history = q.history[historyLength-constants.HistorySize:]
+	}
+
+	// if the file has grown too large, trim the oldest entries
+	if len(entries) > constants.HistoryFileSize {
+		if err := q.compact(entries[len(entries)-constants.HistoryFileSize:]); err != nil {
+			log.Printf("[WARN] failed to compact query history: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// compact rewrites the history file with the given entries
+// the file is written to a temporary file and renamed, so the history file is never left partially written
+// NOTE: entries appended by another instance while compacting will be lost
+func (q *QueryHistory) compact(entries []*HistoryEntry) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	tmpPath := q.path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, q.path)
+}
+
+// migrateLegacyHistory imports the queries from the legacy history file, if there is no history file yet
+func (q *QueryHistory) migrateLegacyHistory(legacyPath string) error {
+	if _, err := os.Stat(q.path); !os.IsNotExist(err) {
+		return nil
+	}
+	data, err := os.ReadFile(legacyPath)
 	if err != nil {
 		// ignore not exists errors
 		if os.IsNotExist(err) {
 			return nil
 		}
 		return err
+	}
 
+	var queries []string
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return err
+	}
+	entries := make([]*HistoryEntry, len(queries))
+	for i, query := range queries {
+		entries[i] = &HistoryEntry{Query: query}
+	}
+	return q.compact(entries)
+}
+
+func readHistoryFile(path string) ([]*HistoryEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		// ignore not exists errors
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
 	}
 	defer file.Close()
 
-	decoder := json.NewDecoder(file)
-	err = decoder.Decode(&q.history)
-	// ignore EOF (caused by empty file)
-	if err == io.EOF {
-		return nil
+	var entries []*HistoryEntry
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry HistoryEntry
+			// skip lines which cannot be parsed (e.g. a partially written entry)
+			if jsonErr := json.Unmarshal(line, &entry); jsonErr == nil {
+				entries = append(entries, &entry)
+			}
+		}
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
-	return err
 }
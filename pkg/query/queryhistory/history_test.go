@@ -0,0 +1,82 @@
+package queryhistory
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHistorySaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+
+	// two instances in different workspaces appending to the same file
+	h1 := newQueryHistory(path, "/ws1")
+	h2 := newQueryHistory(path, "/ws2")
+	for _, q := range []string{"select 1", "select 2"} {
+		if entry := h1.Push(q); entry != nil {
+			entry.RowCount = 1
+			if err := h1.Save(entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if entry := h2.Push(q + " from ws2"); entry != nil {
+			if err := h2.Save(entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// duplicate of the most recent entry is not added
+	if entry := h1.Push("select 2"); entry != nil {
+		t.Errorf("expected duplicate entry to be ignored")
+	}
+
+	loaded := newQueryHistory(path, "/ws1")
+	if err := loaded.load(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"select 1", "select 2"}; !reflect.DeepEqual(loaded.Get(), expected) {
+		t.Errorf("expected %v, got %v", expected, loaded.Get())
+	}
+	if rowCount := loaded.Entries()[0].RowCount; rowCount != 1 {
+		t.Errorf("expected row count 1, got %d", rowCount)
+	}
+}
+
+func TestHistorySearch(t *testing.T) {
+	h := newQueryHistory("", "")
+	for _, q := range []string{"select * from aws_s3_bucket", "select 1", "select name from aws_s3_bucket"} {
+		h.Push(q)
+	}
+
+	entry, idx := h.Search("S3_BUCKET", len(h.Entries()))
+	if idx != 2 || entry.Query != "select name from aws_s3_bucket" {
+		t.Errorf("expected match at 2, got %d", idx)
+	}
+	// continue the search before the previous match
+	if _, idx = h.Search("s3_bucket", idx); idx != 0 {
+		t.Errorf("expected match at 0, got %d", idx)
+	}
+	if _, idx = h.Search("s3_bucket", idx); idx != -1 {
+		t.Errorf("expected no match, got %d", idx)
+	}
+}
+
+func TestMigrateLegacyHistory(t *testing.T) {
+	dir := t.TempDir()
+	legacyPath := filepath.Join(dir, "history.json")
+	if err := os.WriteFile(legacyPath, []byte(`["select 1","select 2"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := newQueryHistory(filepath.Join(dir, "history.jsonl"), "/ws1")
+	if err := h.migrateLegacyHistory(legacyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.load(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"select 1", "select 2"}; !reflect.DeepEqual(h.Get(), expected) {
+		t.Errorf("expected %v, got %v", expected, h.Get())
+	}
+}
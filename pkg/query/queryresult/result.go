@@ -13,6 +13,28 @@ type TimingMetadata struct {
 type TimingResult struct {
 	Duration time.Duration
 	Metadata *TimingMetadata
+	// the query plan and foreign table scans - only populated in explain mode
+	Explain *ExplainResult
+}
+
+// ExplainResult is the Postgres query plan of a query, and the foreign table scans it made
+type ExplainResult struct {
+	Plan  []string
+	Scans []*ScanMetadata
+}
+
+// ScanMetadata is the metadata the FDW reports for a single foreign table scan
+type ScanMetadata struct {
+	Connection   string
+	Table        string
+	Quals        string
+	Columns      []string
+	Limit        *int64
+	RowsFetched  int64
+	CacheHit     bool
+	HydrateCalls int64
+	StartTime    time.Time
+	Duration     time.Duration
 }
 type RowResult struct {
 	Data  []interface{}
@@ -22,6 +44,9 @@ type Result struct {
 	RowChan      *chan *RowResult
 	Cols         []*ColumnDef
 	TimingResult chan *TimingResult
+	// set if the rows were truncated to the max-rows limit
+	// NOTE: this is only valid once all rows have been read
+	Truncated bool
 }
 
 func NewResult(cols []*ColumnDef) *Result {
@@ -54,3 +79,79 @@ type SyncQueryResult struct {
 	Cols         []*ColumnDef
 	TimingResult *TimingResult
 }
+
+// BufferedResult holds the rows of a query result which has been read in full
+// this allows a single result to be replayed several times, e.g. to display it and then export it
+type BufferedResult struct {
+	Cols         []*ColumnDef
+	Rows         []*RowResult
+	Truncated    bool
+	timingResult chan *TimingResult
+}
+
+// NewBufferedResult reads all rows from the given result
+// NOTE: the timing result is NOT read - it is passed through to any Result returned by Replay
+func NewBufferedResult(result *Result) *BufferedResult {
+	b := &BufferedResult{
+		Cols:         result.Cols,
+		timingResult: result.TimingResult,
+	}
+	for row := range *result.RowChan {
+		b.Rows = append(b.Rows, row)
+	}
+	b.Truncated = result.Truncated
+	return b
+}
+
+// Replay returns a new Result which streams the buffered rows
+// NOTE: the returned Result MUST be fully read
+func (b *BufferedResult) Replay() *Result {
+	rowChan := make(chan *RowResult)
+	res := &Result{
+		RowChan:      &rowChan,
+		Cols:         b.Cols,
+		TimingResult: b.timingResult,
+		Truncated:    b.Truncated,
+	}
+	go func() {
+		for _, row := range b.Rows {
+			*res.RowChan <- row
+		}
+		res.Close()
+	}()
+	return res
+}
+
+// Tee returns count Results, each of which streams all rows of the given result
+// this allows a result to be read by several consumers at once (e.g. to display and export it) without holding all rows in memory
+// NOTE: the timing result is passed through to every returned Result, so it must only be read from one of them
+// NOTE: every returned Result MUST be fully read (see Drain)
+func Tee(result *Result, count int) []*Result {
+	results := make([]*Result, count)
+	for i := range results {
+		rowChan := make(chan *RowResult)
+		results[i] = &Result{
+			RowChan:      &rowChan,
+			Cols:         result.Cols,
+			TimingResult: result.TimingResult,
+		}
+	}
+	go func() {
+		for row := range *result.RowChan {
+			for _, r := range results {
+				*r.RowChan <- row
+			}
+		}
+		for _, r := range results {
+			r.Truncated = result.Truncated
+			r.Close()
+		}
+	}()
+	return results
+}
+
+// Drain reads and discards any remaining rows of the result
+func (r *Result) Drain() {
+	for range *r.RowChan {
+	}
+}
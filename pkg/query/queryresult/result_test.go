@@ -0,0 +1,43 @@
+package queryresult
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestTee(t *testing.T) {
+	source := NewResult([]*ColumnDef{{Name: "id", DataType: "INT8"}})
+	go func() {
+		for i := 0; i < 100; i++ {
+			source.StreamRow([]interface{}{int64(i)})
+		}
+		source.Truncated = true
+		source.Close()
+	}()
+
+	results := Tee(source, 3)
+	counts := make([]int, len(results))
+	var wg sync.WaitGroup
+	for i, r := range results {
+		wg.Add(1)
+		go func(i int, r *Result) {
+			defer wg.Done()
+			for row := range *r.RowChan {
+				if row.Data[0] != int64(counts[i]) {
+					t.Errorf("result %d: expected row %d, got %v", i, counts[i], row.Data[0])
+				}
+				counts[i]++
+			}
+		}(i, r)
+	}
+	wg.Wait()
+
+	for i, count := range counts {
+		if count != 100 {
+			t.Errorf("result %d: expected 100 rows, got %d", i, count)
+		}
+		if !results[i].Truncated {
+			t.Errorf("result %d: expected truncated to be passed through", i)
+		}
+	}
+}
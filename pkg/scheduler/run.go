@@ -0,0 +1,223 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/control/controldisplay"
+	"github.com/turbot/steampipe/pkg/control/controlexecute"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/export"
+	"github.com/turbot/steampipe/pkg/filepaths"
+	"github.com/turbot/steampipe/pkg/steampipeconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/workspace"
+)
+
+// runLock ensures only one scheduled run executes at a time
+// loading a workspace reads the mod location, variables and snapshot location from the global viper config,
+// so each run sets these for the duration of the run - see runConfig
+var runLock sync.Mutex
+
+// runBenchmark executes the benchmark of the schedule, then publishes a snapshot and exports the results
+func runBenchmark(ctx context.Context, schedule *modconfig.Schedule) error {
+	runLock.Lock()
+	defer runLock.Unlock()
+
+	var profile *modconfig.WorkspaceProfile
+	if schedule.Workspace != nil {
+		var err error
+		profile, err = loadWorkspaceProfile(*schedule.Workspace)
+		if err != nil {
+			return err
+		}
+	}
+	config, err := newRunConfig(schedule, profile)
+	if err != nil {
+		return err
+	}
+	restoreConfig := config.apply()
+	defer restoreConfig()
+	// if no mod location is set, use the working directory of the plugin manager process
+	modLocation := viper.GetString(constants.ArgModLocation)
+	if modLocation == "" {
+		if modLocation, err = os.Getwd(); err != nil {
+			return err
+		}
+	}
+
+	w, errAndWarnings := workspace.Load(ctx, modLocation)
+	if errAndWarnings.GetError() != nil {
+		return fmt.Errorf("failed to load workspace: %s", errAndWarnings.GetError().Error())
+	}
+	defer w.Close()
+	if !w.ModfileExists() {
+		return workspace.ErrorNoModDefinition
+	}
+
+	client, err := db_local.GetServiceClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close(ctx)
+
+	tree, err := controlexecute.NewExecutionTree(ctx, w, client, "", schedule.Benchmark)
+	if err != nil {
+		return err
+	}
+	if err := tree.Execute(ctx); err != nil {
+		return err
+	}
+	log.Printf("[INFO] scheduled run of '%s' has %d alarms and %d errors", schedule.Name, tree.Root.Summary.Status.Alarm, tree.Root.Summary.Status.Error)
+
+	if viper.GetBool(constants.ArgSnapshot) {
+		if err := controldisplay.PublishSnapshot(ctx, tree, false); err != nil {
+			return err
+		}
+	}
+
+	return exportResults(ctx, tree, schedule, w.Mod.ShortName, modLocation)
+}
+
+// exportResults exports the results of the executed tree to the export targets of the schedule
+// relative export paths are resolved relative to the mod location
+func exportResults(ctx context.Context, tree *controlexecute.ExecutionTree, schedule *modconfig.Schedule, modShortName, modLocation string) error {
+	if len(schedule.Export) == 0 {
+		return nil
+	}
+	if err := controldisplay.EnsureTemplates(); err != nil {
+		return err
+	}
+	exporters, err := controldisplay.GetExporters()
+	if err != nil {
+		return err
+	}
+	exportManager := export.NewManager()
+	for _, e := range exporters {
+		if err := exportManager.Register(e); err != nil {
+			return err
+		}
+	}
+	exportManager.SetExportDir(modLocation)
+	if err := exportManager.ValidateExportFormat(schedule.Export); err != nil {
+		return err
+	}
+
+	parsedName, _ := modconfig.ParseResourceName(schedule.Benchmark)
+	exportName, err := parsedName.ToFullNameWithMod(modShortName)
+	if err != nil {
+		return err
+	}
+	exportMsg, err := exportManager.DoExport(ctx, exportName, tree, schedule.Export)
+	if err != nil {
+		return err
+	}
+	log.Printf("[INFO] scheduled run of '%s' exported results: %s", schedule.Name, strings.Join(exportMsg, ", "))
+	return nil
+}
+
+// loadWorkspaceProfile loads the workspace profile with the given name
+func loadWorkspaceProfile(name string) (*modconfig.WorkspaceProfile, error) {
+	workspaceProfileDir, err := filepaths.WorkspaceProfileDir(filepaths.SteampipeDir)
+	if err != nil {
+		return nil, err
+	}
+	// the loader resolves the configured profile from viper
+	restoreConfig := runConfig{constants.ArgWorkspaceProfile: name}.apply()
+	defer restoreConfig()
+
+	loader, err := steampipeconfig.NewWorkspaceProfileLoader(workspaceProfileDir)
+	if err != nil {
+		return nil, err
+	}
+	return loader.GetActiveWorkspaceProfile(), nil
+}
+
+// runConfig is a map of the viper config values for a scheduled run, keyed by viper key
+type runConfig map[string]any
+
+// newRunConfig builds the config for a run of the schedule
+// the mod location and snapshot location of the schedule take precedence over those of the workspace profile (if any)
+func newRunConfig(schedule *modconfig.Schedule, profile *modconfig.WorkspaceProfile) (runConfig, error) {
+	config := runConfig{
+		constants.ArgInput:    false,
+		constants.ArgProgress: false,
+		// the plugin manager has no terminal to prompt on
+		constants.ConfigKeyBypassHomeDirModfileWarning: true,
+		constants.ArgVarFile:                           schedule.VarFiles,
+	}
+
+	var modLocation, snapshotLocation *string
+	if profile != nil {
+		// scheduled runs always use the service database
+		if profile.WorkspaceDatabase != nil && *profile.WorkspaceDatabase != "local" {
+			return nil, fmt.Errorf("schedule '%s': workspace '%s' has workspace_database '%s' - scheduled runs can only use the local database", schedule.Name, profile.ProfileName, *profile.WorkspaceDatabase)
+		}
+		modLocation = profile.ModLocation
+		snapshotLocation = profile.SnapshotLocation
+		if profile.CloudHost != nil {
+			config[constants.ArgCloudHost] = *profile.CloudHost
+		}
+		if profile.CloudToken != nil {
+			config[constants.ArgCloudToken] = *profile.CloudToken
+		}
+	}
+	if schedule.ModLocation != nil {
+		modLocation = schedule.ModLocation
+	}
+	if schedule.SnapshotLocation != nil {
+		snapshotLocation = schedule.SnapshotLocation
+	}
+
+	if modLocation != nil {
+		config[constants.ArgModLocation] = *modLocation
+	}
+	if snapshotLocation != nil {
+		location := *snapshotLocation
+		// a local snapshot location is relative to the mod location
+		if !steampipeconfig.IsCloudWorkspaceIdentifier(location) && !filepath.IsAbs(location) && modLocation != nil {
+			location = filepath.Join(*modLocation, location)
+		}
+		config[constants.ArgSnapshot] = true
+		config[constants.ArgSnapshotLocation] = location
+	} else {
+		config[constants.ArgSnapshot] = false
+	}
+
+	// sort the variables so the config is deterministic
+	var variableNames []string
+	for name := range schedule.Variables {
+		variableNames = append(variableNames, name)
+	}
+	sort.Strings(variableNames)
+	variables := make([]string, len(variableNames))
+	for i, name := range variableNames {
+		variables[i] = fmt.Sprintf("%s=%s", name, schedule.Variables[name])
+	}
+	config[constants.ArgVariable] = variables
+
+	return config, nil
+}
+
+// apply sets the config values in viper, and returns a function which restores the previous values
+// (setting a nil value removes the override, so values which were not previously set are unset again)
+func (c runConfig) apply() (restore func()) {
+	previous := make(map[string]any, len(c))
+	for key, value := range c {
+		previous[key] = viper.Get(key)
+		viper.Set(key, value)
+	}
+	return func() {
+		for key, value := range previous {
+			viper.Set(key, value)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+// Scheduler runs the benchmarks defined by schedule config blocks on their cron schedules
+// schedules only run while the service was started by 'steampipe service start'
+//
+// each run builds an ExecutionTree for the benchmark in this (the plugin manager) process and exports the results
+// with an export.Manager - see runBenchmark
+type Scheduler struct {
+	// map of running schedules, keyed by schedule name
+	schedules map[string]*scheduledRun
+	mut       sync.Mutex
+	wg        sync.WaitGroup
+	// functions used to get the service state and execute a run (replaced in tests)
+	getServiceState func() (*db_local.RunningDBInstanceInfo, error)
+	runBenchmark    func(context.Context, *modconfig.Schedule) error
+}
+
+type scheduledRun struct {
+	schedule   *modconfig.Schedule
+	expression cron.Schedule
+	cancel     context.CancelFunc
+	// closed when the schedule has stopped
+	done chan struct{}
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		schedules:       make(map[string]*scheduledRun),
+		getServiceState: db_local.GetState,
+		runBenchmark:    runBenchmark,
+	}
+}
+
+// SetSchedules starts any new schedules, restarts any changed schedules and stops any removed schedules
+func (s *Scheduler) SetSchedules(schedules map[string]*modconfig.Schedule) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	for name, run := range s.schedules {
+		if newSchedule, ok := schedules[name]; !ok || !newSchedule.Equals(run.schedule) {
+			log.Printf("[INFO] stopping schedule '%s'", name)
+			run.cancel()
+			delete(s.schedules, name)
+		}
+	}
+
+	for name, schedule := range schedules {
+		if _, ok := s.schedules[name]; ok {
+			continue
+		}
+		// the cron expression has already been validated when the config was parsed
+		expression, err := cron.ParseStandard(schedule.Cron)
+		if err != nil {
+			log.Printf("[WARN] not starting schedule '%s': %s", name, err.Error())
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		run := &scheduledRun{
+			schedule:   schedule,
+			expression: expression,
+			cancel:     cancel,
+			done:       make(chan struct{}),
+		}
+		s.schedules[name] = run
+		log.Printf("[INFO] starting %s", schedule)
+		s.wg.Add(1)
+		go s.runSchedule(ctx, run)
+	}
+}
+
+// Stop stops all schedules, cancelling any runs in progress, and waits for them to finish
+func (s *Scheduler) Stop() {
+	s.mut.Lock()
+	for name, run := range s.schedules {
+		run.cancel()
+		delete(s.schedules, name)
+	}
+	s.mut.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Scheduler) runSchedule(ctx context.Context, run *scheduledRun) {
+	defer s.wg.Done()
+	defer close(run.done)
+
+	for {
+		next := run.expression.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("[WARN] %s will never run", run.schedule)
+			return
+		}
+		log.Printf("[TRACE] next run of schedule '%s' is at %s", run.schedule.Name, next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		// NOTE: runs are executed synchronously, so if a run takes longer than the schedule interval,
+		// the runs which would have started while it was running are skipped
+		if err := s.executeRun(ctx, run.schedule); err != nil {
+			log.Printf("[WARN] scheduled run of '%s' failed: %s", run.schedule.Name, err.Error())
+		}
+	}
+}
+
+func (s *Scheduler) executeRun(ctx context.Context, schedule *modconfig.Schedule) error {
+	// only run schedules when the service has been started explicitly
+	// (the plugin manager is also started when the service is started implicitly by a query or check)
+	state, err := s.getServiceState()
+	if err != nil {
+		return err
+	}
+	if state == nil || state.Invoker != constants.InvokerService {
+		log.Printf("[INFO] skipping scheduled run of '%s' as the service was not started by 'steampipe service start'", schedule.Name)
+		return nil
+	}
+
+	log.Printf("[INFO] starting scheduled run of '%s'", schedule.Name)
+	start := time.Now()
+
+	if err := s.runBenchmark(ctx, schedule); err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] scheduled run of '%s' completed in %s", schedule.Name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,229 @@
+package scheduler
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/db/db_local"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func newTestSchedule(name, benchmark string) *modconfig.Schedule {
+	// the first run is an hour away, so no runs are executed by the tests
+	return &modconfig.Schedule{Name: name, Benchmark: benchmark, Cron: "@every 1h"}
+}
+
+func TestSetSchedules(t *testing.T) {
+	s := NewScheduler()
+	defer s.Stop()
+
+	s.SetSchedules(map[string]*modconfig.Schedule{
+		"unchanged": newTestSchedule("unchanged", "benchmark.b1"),
+		"changed":   newTestSchedule("changed", "benchmark.b2"),
+		"removed":   newTestSchedule("removed", "benchmark.b3"),
+	})
+	unchanged := s.schedules["unchanged"]
+	changed := s.schedules["changed"]
+	removed := s.schedules["removed"]
+
+	s.SetSchedules(map[string]*modconfig.Schedule{
+		"unchanged": newTestSchedule("unchanged", "benchmark.b1"),
+		"changed":   newTestSchedule("changed", "benchmark.b4"),
+		"added":     newTestSchedule("added", "benchmark.b5"),
+	})
+
+	if len(s.schedules) != 3 {
+		t.Errorf("expected 3 schedules, got %d", len(s.schedules))
+	}
+	if s.schedules["unchanged"] != unchanged {
+		t.Error("expected the unchanged schedule to keep running")
+	}
+	if run := s.schedules["changed"]; run == changed || run.schedule.Benchmark != "benchmark.b4" {
+		t.Error("expected the changed schedule to be restarted with the new config")
+	}
+	if _, ok := s.schedules["removed"]; ok {
+		t.Error("expected the removed schedule to be stopped")
+	}
+	if _, ok := s.schedules["added"]; !ok {
+		t.Error("expected the added schedule to be started")
+	}
+
+	// the replaced and removed schedules are stopped
+	for name, run := range map[string]*scheduledRun{"changed": changed, "removed": removed} {
+		select {
+		case <-run.done:
+		case <-time.After(5 * time.Second):
+			t.Errorf("expected the %s schedule to stop", name)
+		}
+	}
+	select {
+	case <-unchanged.done:
+		t.Error("expected the unchanged schedule to keep running")
+	default:
+	}
+
+	s.Stop()
+	if len(s.schedules) != 0 {
+		t.Errorf("expected no schedules after Stop, got %d", len(s.schedules))
+	}
+}
+
+func TestExecuteRunOnlyRunsForService(t *testing.T) {
+	tests := map[string]struct {
+		state       *db_local.RunningDBInstanceInfo
+		expectedRun bool
+	}{
+		"service not running": {state: nil, expectedRun: false},
+		"started by query":    {state: &db_local.RunningDBInstanceInfo{Invoker: constants.InvokerQuery}, expectedRun: false},
+		"started by service":  {state: &db_local.RunningDBInstanceInfo{Invoker: constants.InvokerService}, expectedRun: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ran := false
+			s := NewScheduler()
+			s.getServiceState = func() (*db_local.RunningDBInstanceInfo, error) { return test.state, nil }
+			s.runBenchmark = func(context.Context, *modconfig.Schedule) error {
+				ran = true
+				return nil
+			}
+			if err := s.executeRun(context.Background(), newTestSchedule("s1", "benchmark.b1")); err != nil {
+				t.Fatal(err)
+			}
+			if ran != test.expectedRun {
+				t.Errorf("expected run %v, got %v", test.expectedRun, ran)
+			}
+		})
+	}
+}
+
+func TestNewRunConfig(t *testing.T) {
+	modLocation := "/mods/cis"
+	profileModLocation := "/mods/profile"
+	snapshotLocation := "snapshots"
+	cloudSnapshotLocation := "acme/prod"
+	cloudToken := "token"
+	cloudDatabase := "acme/prod"
+
+	baseConfig := func(extra runConfig) runConfig {
+		config := runConfig{
+			constants.ArgInput:                             false,
+			constants.ArgProgress:                          false,
+			constants.ConfigKeyBypassHomeDirModfileWarning: true,
+			constants.ArgVarFile:                           []string(nil),
+			constants.ArgVariable:                          []string{},
+			constants.ArgSnapshot:                          false,
+		}
+		for k, v := range extra {
+			config[k] = v
+		}
+		return config
+	}
+
+	tests := map[string]struct {
+		schedule      *modconfig.Schedule
+		profile       *modconfig.WorkspaceProfile
+		expected      runConfig
+		expectedError string
+	}{
+		"no settings": {
+			schedule: &modconfig.Schedule{Name: "s1"},
+			expected: baseConfig(nil),
+		},
+		"variables and var files": {
+			schedule: &modconfig.Schedule{
+				Name:      "s1",
+				Variables: map[string]string{"region": "us-east-1", "account": "123"},
+				VarFiles:  []string{"prod.spvars"},
+			},
+			expected: baseConfig(runConfig{
+				// the variables are sorted by name
+				constants.ArgVariable: []string{"account=123", "region=us-east-1"},
+				constants.ArgVarFile:  []string{"prod.spvars"},
+			}),
+		},
+		"relative snapshot location": {
+			schedule: &modconfig.Schedule{Name: "s1", ModLocation: &modLocation, SnapshotLocation: &snapshotLocation},
+			expected: baseConfig(runConfig{
+				constants.ArgModLocation:      modLocation,
+				constants.ArgSnapshot:         true,
+				constants.ArgSnapshotLocation: "/mods/cis/snapshots",
+			}),
+		},
+		"cloud snapshot location": {
+			schedule: &modconfig.Schedule{Name: "s1", ModLocation: &modLocation, SnapshotLocation: &cloudSnapshotLocation},
+			expected: baseConfig(runConfig{
+				constants.ArgModLocation:      modLocation,
+				constants.ArgSnapshot:         true,
+				constants.ArgSnapshotLocation: cloudSnapshotLocation,
+			}),
+		},
+		"workspace profile": {
+			schedule: &modconfig.Schedule{Name: "s1"},
+			profile:  &modconfig.WorkspaceProfile{ModLocation: &profileModLocation, SnapshotLocation: &cloudSnapshotLocation, CloudToken: &cloudToken},
+			expected: baseConfig(runConfig{
+				constants.ArgModLocation:      profileModLocation,
+				constants.ArgSnapshot:         true,
+				constants.ArgSnapshotLocation: cloudSnapshotLocation,
+				constants.ArgCloudToken:       cloudToken,
+			}),
+		},
+		"schedule overrides workspace profile": {
+			schedule: &modconfig.Schedule{Name: "s1", ModLocation: &modLocation},
+			profile:  &modconfig.WorkspaceProfile{ModLocation: &profileModLocation},
+			expected: baseConfig(runConfig{
+				constants.ArgModLocation: modLocation,
+			}),
+		},
+		"workspace profile with cloud database": {
+			schedule:      &modconfig.Schedule{Name: "s1"},
+			profile:       &modconfig.WorkspaceProfile{ProfileName: "prod", WorkspaceDatabase: &cloudDatabase},
+			expectedError: "scheduled runs can only use the local database",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, err := newRunConfig(test.schedule, test.profile)
+			if test.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+					t.Errorf("expected error '%s', got %v", test.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(config, test.expected) {
+				t.Errorf("newRunConfig() = %v, expected %v", config, test.expected)
+			}
+		})
+	}
+}
+
+func TestRunConfigApply(t *testing.T) {
+	defer viper.Reset()
+	viper.Set(constants.ArgModLocation, "/mods/original")
+
+	restore := runConfig{
+		constants.ArgModLocation: "/mods/cis",
+		constants.ArgVariable:    []string{"region=us-east-1"},
+	}.apply()
+	if got := viper.GetString(constants.ArgModLocation); got != "/mods/cis" {
+		t.Errorf("expected the mod location to be set while the run executes, got %s", got)
+	}
+	if got := viper.GetStringSlice(constants.ArgVariable); !reflect.DeepEqual(got, []string{"region=us-east-1"}) {
+		t.Errorf("expected the variables to be set while the run executes, got %v", got)
+	}
+
+	restore()
+	if got := viper.GetString(constants.ArgModLocation); got != "/mods/original" {
+		t.Errorf("expected the mod location to be restored, got %s", got)
+	}
+	if viper.IsSet(constants.ArgVariable) {
+		t.Errorf("expected the variables to be unset, got %v", viper.Get(constants.ArgVariable))
+	}
+}
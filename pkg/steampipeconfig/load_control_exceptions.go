@@ -0,0 +1,61 @@
+package steampipeconfig
+
+import (
+	"log"
+
+	filehelpers "github.com/turbot/go-kit/files"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+	"github.com/turbot/steampipe/pkg/constants"
+	"github.com/turbot/steampipe/pkg/error_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/parse"
+)
+
+// LoadControlExceptions loads the exception blocks from all .spexceptions files in the given mod path
+func LoadControlExceptions(modPath string) (modconfig.ControlExceptions, *error_helpers.ErrorAndWarnings) {
+	exceptionPaths, err := filehelpers.ListFiles(modPath, &filehelpers.ListOptions{
+		Flags:   filehelpers.FilesFlat,
+		Include: filehelpers.InclusionsFromExtensions([]string{constants.ExceptionsExtension}),
+	})
+	if err != nil {
+		return nil, error_helpers.NewErrorsAndWarning(err)
+	}
+	if len(exceptionPaths) == 0 {
+		return nil, nil
+	}
+	log.Printf("[INFO] loading control exceptions from %v", exceptionPaths)
+
+	fileData, diags := parse.LoadFileData(exceptionPaths...)
+	if diags.HasErrors() {
+		return nil, error_helpers.DiagsToErrorsAndWarnings("Failed to load exception files", diags)
+	}
+	body, diags := parse.ParseHclFiles(fileData)
+	if diags.HasErrors() {
+		return nil, error_helpers.DiagsToErrorsAndWarnings("Failed to load exception files", diags)
+	}
+	content, diags := body.Content(parse.ExceptionsBlockSchema)
+	if diags.HasErrors() {
+		return nil, error_helpers.DiagsToErrorsAndWarnings("Failed to load exceptions", diags)
+	}
+
+	var exceptions modconfig.ControlExceptions
+	exceptionMap := make(map[string]*modconfig.ControlException)
+	for _, block := range content.Blocks {
+		exception, moreDiags := parse.DecodeControlException(block)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		if existing, ok := exceptionMap[exception.Name]; ok {
+			return nil, error_helpers.NewErrorsAndWarning(sperr.New("duplicate exception name: '%s'\n\t(%s:%d)\n\t(%s:%d)",
+				exception.Name, existing.DeclRange.Filename, existing.DeclRange.Start.Line,
+				exception.DeclRange.Filename, exception.DeclRange.Start.Line))
+		}
+		exceptionMap[exception.Name] = exception
+		exceptions = append(exceptions, exception)
+	}
+	if diags.HasErrors() {
+		return nil, error_helpers.DiagsToErrorsAndWarnings("Failed to load exceptions", diags)
+	}
+	return exceptions, error_helpers.DiagsToErrorsAndWarnings("", diags)
+}
@@ -38,6 +38,8 @@ const (
 	BlockTypeConnection       = "connection"
 	BlockTypeOptions          = "options"
 	BlockTypeWorkspaceProfile = "workspace"
+	BlockTypeSchedule         = "schedule"
+	BlockTypeException        = "exception"
 
 	ResourceTypeSnapshot = "snapshot"
 	AttributeArgs        = "args"
@@ -0,0 +1,88 @@
+package modconfig
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/go-kit/hcl_helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/sperr"
+)
+
+// ControlException marks alarms raised by a control as accepted risk - matching results are reported as 'suppressed'
+type ControlException struct {
+	Name string `hcl:"name,label" json:"name"`
+	// the control the exception applies to, either as 'control.<name>' or '<mod>.control.<name>'
+	Control string `hcl:"control" json:"control"`
+	// the resources the exception applies to - if empty, the exception applies to all resources of the control
+	Resources     []string `hcl:"resources,optional" json:"resources,omitempty"`
+	Justification string   `hcl:"justification" json:"justification"`
+	// the date (YYYY-MM-DD) or time (RFC3339) after which the exception no longer applies
+	Expires *string `hcl:"expires,optional" json:"expires,omitempty"`
+
+	ExpiryTime *time.Time `json:"-"`
+	DeclRange  hcl.Range  `json:"-"`
+}
+
+func (e *ControlException) OnDecoded(block *hcl.Block) error {
+	e.DeclRange = hcl_helpers.BlockRange(block)
+	if !strings.Contains(e.Control, BlockTypeControl+".") {
+		e.Control = fmt.Sprintf("%s.%s", BlockTypeControl, e.Control)
+	}
+	if e.Expires != nil {
+		expiryTime, err := parseExceptionExpiry(*e.Expires)
+		if err != nil {
+			return err
+		}
+		e.ExpiryTime = &expiryTime
+	}
+	return nil
+}
+
+// parseExceptionExpiry parses an expiry date or time
+// a date expires at the end of that day (UTC)
+func parseExceptionExpiry(expires string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, expires); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, expires)
+	if err != nil {
+		return time.Time{}, sperr.New("invalid expiry '%s' - must be a date (YYYY-MM-DD) or an RFC3339 time", expires)
+	}
+	return t.AddDate(0, 0, 1), nil
+}
+
+func (e *ControlException) IsExpired(now time.Time) bool {
+	return e.ExpiryTime != nil && !now.Before(*e.ExpiryTime)
+}
+
+// Matches returns whether the exception applies to the given control and resource
+func (e *ControlException) Matches(control *Control, resource string) bool {
+	// the control may be specified with or without the mod name
+	if e.Control != control.Name() && e.Control != control.UnqualifiedName {
+		return false
+	}
+	if len(e.Resources) == 0 {
+		return true
+	}
+	for _, r := range e.Resources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// ControlExceptions is the set of control exceptions loaded for a workspace
+type ControlExceptions []*ControlException
+
+// Find returns the first unexpired exception which applies to the given control and resource (if any)
+func (e ControlExceptions) Find(control *Control, resource string, now time.Time) *ControlException {
+	for _, exception := range e {
+		if !exception.IsExpired(now) && exception.Matches(control, resource) {
+			return exception
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package modconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestControlExceptionsFind(t *testing.T) {
+	control := &Control{}
+	control.FullName = "aws_compliance.control.s3_bucket_versioning_enabled"
+	control.UnqualifiedName = "control.s3_bucket_versioning_enabled"
+	expires := "2023-06-30"
+	exceptions := ControlExceptions{
+		{Name: "expired", Control: "control.s3_bucket_versioning_enabled", Expires: &expires},
+		{Name: "legacy_bucket", Control: "aws_compliance.control.s3_bucket_versioning_enabled", Resources: []string{"arn:aws:s3:::legacy"}},
+		{Name: "other_control", Control: "control.other"},
+	}
+	for _, e := range exceptions {
+		if err := e.OnDecoded(&hcl.Block{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		resource string
+		now      time.Time
+		expected string
+	}{
+		"unexpired, all resources":   {"arn:aws:s3:::other", time.Date(2023, 6, 30, 23, 59, 0, 0, time.UTC), "expired"},
+		"expired, no match":          {"arn:aws:s3:::other", time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), ""},
+		"expired, matching resource": {"arn:aws:s3:::legacy", time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), "legacy_bucket"},
+	}
+	for name, test := range tests {
+		var got string
+		if e := exceptions.Find(control, test.resource, test.now); e != nil {
+			got = e.Name
+		}
+		if got != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", name, test.expected, got)
+		}
+	}
+}
@@ -28,6 +28,7 @@ type Dashboard struct {
 
 	Width   *int              `cty:"width" hcl:"width"  column:"width,text"`
 	Display *string           `cty:"display" hcl:"display" column:"display,text"`
+	Refresh *int              `cty:"refresh" hcl:"refresh" column:"refresh,text"` // seconds between re-executions by the dashboard server (0 to disable)
 	Inputs  []*DashboardInput `cty:"inputs" column:"inputs,jsonb"`
 	UrlPath string            `cty:"url_path"  column:"url_path,jsonb"`
 	Base    *Dashboard        `hcl:"base"`
@@ -147,6 +148,14 @@ func (d *Dashboard) Equals(other *Dashboard) bool {
 func (d *Dashboard) OnDecoded(block *hcl.Block, _ ResourceMapsProvider) hcl.Diagnostics {
 	d.setBaseProperties()
 
+	if d.Refresh != nil && *d.Refresh < 0 {
+		return hcl.Diagnostics{&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("%s: refresh must be a number of seconds, or 0 to disable refresh", d.Name()),
+			Subject:  hcl_helpers.BlockRangePointer(block),
+		}}
+	}
+
 	d.ChildNames = make([]string, len(d.children))
 	for i, child := range d.children {
 		d.ChildNames[i] = child.Name()
@@ -191,6 +200,10 @@ func (d *Dashboard) Diff(other *Dashboard) *DashboardTreeItemDiffs {
 		res.AddPropertyDiff("Width")
 	}
 
+	if !utils.SafeIntEqual(d.Refresh, other.Refresh) {
+		res.AddPropertyDiff("Refresh")
+	}
+
 	if len(d.Tags) != len(other.Tags) {
 		res.AddPropertyDiff("Tags")
 	} else {
@@ -417,6 +430,10 @@ func (d *Dashboard) setBaseProperties() {
 		d.Width = d.Base.Width
 	}
 
+	if d.Refresh == nil {
+		d.Refresh = d.Base.Refresh
+	}
+
 	if len(d.children) == 0 {
 		d.children = d.Base.children
 		d.ChildNames = d.Base.ChildNames
@@ -0,0 +1,64 @@
+package modconfig
+
+import (
+	"strings"
+)
+
+// MissingParamsError is returned when resolving the args of a query provider
+// if there are params with no arg value and no default
+type MissingParamsError struct {
+	QueryProvider QueryProvider
+	// the args which were resolved (merged with the base args of the query provider)
+	Args   *QueryArgs
+	Params []*ParamDef
+}
+
+func newMissingParamsError(queryProvider QueryProvider, args *QueryArgs, missingParams []string) *MissingParamsError {
+	res := &MissingParamsError{
+		QueryProvider: queryProvider,
+		Args:          args,
+	}
+	for _, param := range queryProvider.GetParams() {
+		for _, name := range missingParams {
+			if param.ShortName == name {
+				res.Params = append(res.Params, param)
+			}
+		}
+	}
+	return res
+}
+
+func (e *MissingParamsError) Error() string {
+	names := make([]string, len(e.Params))
+	for i, param := range e.Params {
+		names[i] = param.ShortName
+	}
+	return strings.Join(names, ",")
+}
+
+// ResolveWithValues resolves the query with the given values for the missing params (keyed by param name)
+func (e *MissingParamsError) ResolveWithValues(values map[string]any) (*ResolvedQuery, error) {
+	paramArgs := NewQueryArgs()
+	// the values must be passed in the same way as the existing args (i.e. either positional or named)
+	if len(e.Args.ArgList) > 0 {
+		params := e.QueryProvider.GetParams()
+		paramArgs.ArgList = make([]*string, len(params))
+		for i, param := range params {
+			if value, ok := values[param.ShortName]; ok {
+				if err := paramArgs.SetPositionalArgVal(value, i); err != nil {
+					return nil, err
+				}
+			}
+		}
+	} else {
+		if err := paramArgs.SetArgMap(values); err != nil {
+			return nil, err
+		}
+	}
+
+	args, err := e.Args.Merge(paramArgs, e.QueryProvider)
+	if err != nil {
+		return nil, err
+	}
+	return e.QueryProvider.GetResolvedQuery(args)
+}
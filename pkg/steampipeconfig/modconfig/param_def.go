@@ -70,3 +70,49 @@ func (p *ParamDef) GetDefault() (any, error) {
 	err := json.Unmarshal([]byte(*p.Default), &val)
 	return val, err
 }
+
+// ParseValue converts a value entered by the user into an arg value for this param
+// if the param has a default, the value must have the same type as the default
+// otherwise the value is parsed as JSON if possible (so numbers, booleans and arrays may be entered), falling back to a string
+func (p *ParamDef) ParseValue(rawValue string) (any, error) {
+	if p.Default != nil && p.IsString {
+		return rawValue, nil
+	}
+
+	var value any
+	jsonErr := json.Unmarshal([]byte(rawValue), &value)
+	if p.Default == nil {
+		if jsonErr != nil {
+			return rawValue, nil
+		}
+		return value, nil
+	}
+
+	// so the default is not a string - the value must be valid JSON of the same type
+	defaultValue, err := p.GetDefault()
+	if err != nil {
+		return nil, err
+	}
+	expectedType := jsonTypeName(defaultValue)
+	if jsonErr != nil || jsonTypeName(value) != expectedType {
+		return nil, fmt.Errorf("'%s' is not a valid value for %s - expected %s", rawValue, p.UnqualifiedName, expectedType)
+	}
+	return value, nil
+}
+
+// jsonTypeName returns the name of the type of a value unmarshalled from JSON
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	case string:
+		return "a string"
+	}
+	return "null"
+}
@@ -0,0 +1,90 @@
+package modconfig
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParamDefParseValue(t *testing.T) {
+	stringDefault := &ParamDef{UnqualifiedName: "param.p1"}
+	if err := stringDefault.SetDefault("val"); err != nil {
+		t.Fatal(err)
+	}
+	numberDefault := &ParamDef{UnqualifiedName: "param.p2"}
+	if err := numberDefault.SetDefault(10); err != nil {
+		t.Fatal(err)
+	}
+	noDefault := &ParamDef{UnqualifiedName: "param.p3"}
+
+	tests := []struct {
+		param    *ParamDef
+		rawValue string
+		expected any
+	}{
+		{stringDefault, "123", "123"},
+		{numberDefault, "123", float64(123)},
+		{numberDefault, "abc", "ERROR"},
+		{numberDefault, `"123"`, "ERROR"},
+		{noDefault, "abc", "abc"},
+		{noDefault, "true", true},
+		{noDefault, `["a","b"]`, []any{"a", "b"}},
+		{noDefault, `"123"`, "123"},
+	}
+	for _, test := range tests {
+		value, err := test.param.ParseValue(test.rawValue)
+		if err != nil {
+			if test.expected != "ERROR" {
+				t.Errorf("%s '%s': unexpected error %v", test.param.UnqualifiedName, test.rawValue, err)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("%s '%s': expected %v, got %v", test.param.UnqualifiedName, test.rawValue, test.expected, value)
+		}
+	}
+}
+
+func TestMissingParamsErrorResolveWithValues(t *testing.T) {
+	sql := "select $1, $2"
+	query := &Control{
+		QueryProviderImpl: QueryProviderImpl{
+			RuntimeDependencyProviderImpl: RuntimeDependencyProviderImpl{
+				ModTreeItemImpl: ModTreeItemImpl{
+					HclResourceImpl: HclResourceImpl{
+						FullName: "control.test_control",
+					},
+				},
+			},
+			SQL:    &sql,
+			Params: []*ParamDef{{ShortName: "p1"}, {ShortName: "p2"}},
+		},
+	}
+
+	for name, runtimeArgs := range map[string]*QueryArgs{
+		"no args":         nil,
+		"positional args": {ArgList: []*string{&[]string{`"val1"`}[0]}},
+	} {
+		_, err := query.GetResolvedQuery(runtimeArgs)
+		var missingParamsErr *MissingParamsError
+		if !errors.As(err, &missingParamsErr) {
+			t.Fatalf("%s: expected MissingParamsError, got %v", name, err)
+		}
+
+		values := map[string]any{}
+		for _, param := range missingParamsErr.Params {
+			values[param.ShortName] = param.ShortName + "_prompted"
+		}
+		resolvedQuery, err := missingParamsErr.ResolveWithValues(values)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", name, err)
+		}
+		expected := []any{"p1_prompted", "p2_prompted"}
+		if runtimeArgs != nil {
+			expected[0] = "val1"
+		}
+		if !reflect.DeepEqual(resolvedQuery.Args, expected) {
+			t.Errorf("%s: expected %v, got %v", name, expected, resolvedQuery.Args)
+		}
+	}
+}
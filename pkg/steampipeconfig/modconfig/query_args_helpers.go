@@ -1,10 +1,8 @@
 package modconfig
 
 import (
-	"fmt"
 	"github.com/turbot/go-kit/type_conversion"
 	"log"
-	"strings"
 
 	"github.com/turbot/steampipe/pkg/utils"
 )
@@ -66,7 +64,7 @@ func ResolveArgs(qp QueryProvider, runtimeArgs *QueryArgs) ([]any, error) {
 	// did we resolve them all?
 	if len(missingParams) > 0 {
 		// a better error will be constructed by the calling code
-		return nil, fmt.Errorf("%s", strings.Join(missingParams, ","))
+		return nil, newMissingParamsError(qp, mergedArgs, missingParams)
 	}
 
 	// are there any params?
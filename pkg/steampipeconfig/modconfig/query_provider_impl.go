@@ -91,7 +91,7 @@ func (q *QueryProviderImpl) RequiresExecution(queryProvider QueryProvider) bool
 func (q *QueryProviderImpl) GetResolvedQuery(runtimeArgs *QueryArgs) (*ResolvedQuery, error) {
 	argsArray, err := ResolveArgs(q, runtimeArgs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve args for %s: %s", q.Name(), err.Error())
+		return nil, fmt.Errorf("failed to resolve args for %s: %w", q.Name(), err)
 	}
 	sql := typehelpers.SafeString(q.GetSQL())
 	// we expect there to be sql on the query provider, NOT a Query
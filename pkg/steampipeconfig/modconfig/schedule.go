@@ -0,0 +1,61 @@
+package modconfig
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/turbot/go-kit/hcl_helpers"
+)
+
+// Schedule is a config block which defines a benchmark to be run by the service on a cron schedule
+type Schedule struct {
+	Name string `hcl:"name,label"`
+	// the benchmark to run, e.g. benchmark.cis_v150
+	Benchmark string `hcl:"benchmark"`
+	// a standard 5 field cron expression (or a descriptor such as @daily or @every 1h)
+	Cron string `hcl:"cron"`
+	// the mod location to run the benchmark from
+	// if this is not set, the run uses the working directory of the plugin manager process - i.e. the directory of
+	// the steampipe command which started the service - so it should normally be set
+	ModLocation *string `hcl:"mod_location,optional"`
+	// the workspace profile to use
+	Workspace *string `hcl:"workspace,optional"`
+	// export targets, e.g. "json" or "output/result.csv"
+	Export []string `hcl:"export,optional"`
+	// if set, a snapshot of the run is saved to this location
+	SnapshotLocation *string           `hcl:"snapshot_location,optional"`
+	Variables        map[string]string `hcl:"variables,optional"`
+	VarFiles         []string          `hcl:"var_files,optional"`
+
+	FileName        *string
+	StartLineNumber *int
+	EndLineNumber   *int
+}
+
+func (s *Schedule) OnDecoded(block *hcl.Block) {
+	scheduleRange := hcl_helpers.BlockRange(block)
+	s.FileName = &scheduleRange.Filename
+	s.StartLineNumber = &scheduleRange.Start.Line
+	s.EndLineNumber = &scheduleRange.End.Line
+}
+
+// Equals returns whether the run configuration of the schedules is the same (the declaration range is ignored)
+func (s *Schedule) Equals(other *Schedule) bool {
+	if other == nil {
+		return false
+	}
+	return s.Name == other.Name &&
+		s.Benchmark == other.Benchmark &&
+		s.Cron == other.Cron &&
+		reflect.DeepEqual(s.ModLocation, other.ModLocation) &&
+		reflect.DeepEqual(s.Workspace, other.Workspace) &&
+		reflect.DeepEqual(s.Export, other.Export) &&
+		reflect.DeepEqual(s.SnapshotLocation, other.SnapshotLocation) &&
+		reflect.DeepEqual(s.Variables, other.Variables) &&
+		reflect.DeepEqual(s.VarFiles, other.VarFiles)
+}
+
+func (s *Schedule) String() string {
+	return fmt.Sprintf("schedule.%s (%s '%s')", s.Name, s.Benchmark, s.Cron)
+}
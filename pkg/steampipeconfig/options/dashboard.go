@@ -18,6 +18,24 @@ type GlobalDashboard struct {
 	Port         *int    `hcl:"port"`
 	Listen       *string `hcl:"listen"`
 	StartTimeout *int    `hcl:"start_timeout"`
+	// the default interval in seconds at which running dashboards are re-executed
+	// (overridden by the dashboard 'refresh' property)
+	RefreshInterval *int `hcl:"refresh_interval"`
+	// the time in seconds that query results are shared between dashboard sessions
+	CacheTtl *int `hcl:"cache_ttl"`
+	// authentication - if any of these are set, all requests to the server must be authenticated
+	// map of identity to bearer token
+	AuthTokens *map[string]string `hcl:"auth_tokens"`
+	// map of user name to password, for basic auth
+	AuthUsers *map[string]string `hcl:"auth_users"`
+	// a header set by a reverse proxy which contains the identity of the authenticated user
+	AuthProxyHeader *string `hcl:"auth_proxy_header"`
+	// the addresses (or CIDR ranges) of the reverse proxies which are trusted to set AuthProxyHeader
+	AuthTrustedProxies *[]string `hcl:"auth_trusted_proxies"`
+	// TLS - either provide a certificate and key, or generate a certificate signed by the steampipe root certificate
+	CertificateFile     *string `hcl:"certificate_file"`
+	KeyFile             *string `hcl:"key_file"`
+	GenerateCertificate *bool   `hcl:"generate_certificate"`
 }
 
 func (t *WorkspaceProfileDashboard) SetBaseProperties(otherOptions Options) {
@@ -83,6 +101,33 @@ func (d *GlobalDashboard) ConfigMap() map[string]interface{} {
 	} else {
 		res[constants.ArgDashboardStartTimeout] = constants.DashboardStartTimeout.Seconds()
 	}
+	if d.RefreshInterval != nil {
+		res[constants.ArgDashboardRefreshInterval] = d.RefreshInterval
+	}
+	if d.CacheTtl != nil {
+		res[constants.ArgDashboardCacheTtl] = d.CacheTtl
+	}
+	if d.AuthTokens != nil {
+		res[constants.ArgDashboardAuthTokens] = *d.AuthTokens
+	}
+	if d.AuthUsers != nil {
+		res[constants.ArgDashboardAuthUsers] = *d.AuthUsers
+	}
+	if d.AuthProxyHeader != nil {
+		res[constants.ArgDashboardAuthProxyHeader] = d.AuthProxyHeader
+	}
+	if d.AuthTrustedProxies != nil {
+		res[constants.ArgDashboardAuthTrustedProxies] = *d.AuthTrustedProxies
+	}
+	if d.CertificateFile != nil {
+		res[constants.ArgDashboardCertificateFile] = d.CertificateFile
+	}
+	if d.KeyFile != nil {
+		res[constants.ArgDashboardKeyFile] = d.KeyFile
+	}
+	if d.GenerateCertificate != nil {
+		res[constants.ArgDashboardGenerateCertificate] = d.GenerateCertificate
+	}
 	return res
 }
 
@@ -103,6 +148,33 @@ func (d *GlobalDashboard) Merge(otherOptions Options) {
 		if o.StartTimeout != nil {
 			d.StartTimeout = o.StartTimeout
 		}
+		if o.RefreshInterval != nil {
+			d.RefreshInterval = o.RefreshInterval
+		}
+		if o.CacheTtl != nil {
+			d.CacheTtl = o.CacheTtl
+		}
+		if o.AuthTokens != nil {
+			d.AuthTokens = o.AuthTokens
+		}
+		if o.AuthUsers != nil {
+			d.AuthUsers = o.AuthUsers
+		}
+		if o.AuthProxyHeader != nil {
+			d.AuthProxyHeader = o.AuthProxyHeader
+		}
+		if o.AuthTrustedProxies != nil {
+			d.AuthTrustedProxies = o.AuthTrustedProxies
+		}
+		if o.CertificateFile != nil {
+			d.CertificateFile = o.CertificateFile
+		}
+		if o.KeyFile != nil {
+			d.KeyFile = o.KeyFile
+		}
+		if o.GenerateCertificate != nil {
+			d.GenerateCertificate = o.GenerateCertificate
+		}
 	}
 }
 
@@ -126,5 +198,51 @@ func (d *GlobalDashboard) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  StartTimeout: %d", *d.StartTimeout))
 	}
+	if d.RefreshInterval == nil {
+		str = append(str, "  RefreshInterval: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  RefreshInterval: %d", *d.RefreshInterval))
+	}
+	if d.CacheTtl == nil {
+		str = append(str, "  CacheTtl: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  CacheTtl: %d", *d.CacheTtl))
+	}
+	// do not show the tokens or passwords
+	if d.AuthTokens == nil {
+		str = append(str, "  AuthTokens: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  AuthTokens: %d tokens", len(*d.AuthTokens)))
+	}
+	if d.AuthUsers == nil {
+		str = append(str, "  AuthUsers: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  AuthUsers: %d users", len(*d.AuthUsers)))
+	}
+	if d.AuthProxyHeader == nil {
+		str = append(str, "  AuthProxyHeader: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  AuthProxyHeader: %s", *d.AuthProxyHeader))
+	}
+	if d.AuthTrustedProxies == nil {
+		str = append(str, "  AuthTrustedProxies: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  AuthTrustedProxies: %s", strings.Join(*d.AuthTrustedProxies, ", ")))
+	}
+	if d.CertificateFile == nil {
+		str = append(str, "  CertificateFile: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  CertificateFile: %s", *d.CertificateFile))
+	}
+	if d.KeyFile == nil {
+		str = append(str, "  KeyFile: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  KeyFile: %s", *d.KeyFile))
+	}
+	if d.GenerateCertificate == nil {
+		str = append(str, "  GenerateCertificate: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  GenerateCertificate: %v", *d.GenerateCertificate))
+	}
 	return strings.Join(str, "\n")
 }
@@ -16,6 +16,7 @@ type Query struct {
 	Multi        *bool   `hcl:"multi" cty:"query_multi"`
 	Timing       *bool   `hcl:"timing" cty:"query_timing"`
 	AutoComplete *bool   `hcl:"autocomplete" cty:"query_autocomplete"`
+	MaxRows      *int    `hcl:"max_rows" cty:"query_max_rows"`
 }
 
 func (t *Query) SetBaseProperties(otherOptions Options) {
@@ -41,6 +42,9 @@ func (t *Query) SetBaseProperties(otherOptions Options) {
 		if t.AutoComplete == nil && o.AutoComplete != nil {
 			t.AutoComplete = o.AutoComplete
 		}
+		if t.MaxRows == nil && o.MaxRows != nil {
+			t.MaxRows = o.MaxRows
+		}
 	}
 }
 
@@ -66,6 +70,9 @@ func (t *Query) ConfigMap() map[string]interface{} {
 	if t.AutoComplete != nil {
 		res[constants.ArgAutoComplete] = t.AutoComplete
 	}
+	if t.MaxRows != nil {
+		res[constants.ArgMaxRows] = t.MaxRows
+	}
 	return res
 }
 
@@ -95,6 +102,9 @@ func (t *Query) Merge(otherOptions Options) {
 		if o.AutoComplete != nil {
 			t.AutoComplete = o.AutoComplete
 		}
+		if o.MaxRows != nil {
+			t.MaxRows = o.MaxRows
+		}
 	}
 }
 
@@ -133,5 +143,10 @@ func (t *Query) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  AutoComplete: %v", *t.AutoComplete))
 	}
+	if t.MaxRows == nil {
+		str = append(str, "  MaxRows: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  MaxRows: %d", *t.MaxRows))
+	}
 	return strings.Join(str, "\n")
 }
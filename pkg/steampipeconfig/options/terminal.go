@@ -17,6 +17,7 @@ type Terminal struct {
 	SearchPathPrefix *string `hcl:"search_path_prefix"`
 	Watch            *bool   `hcl:"watch"`
 	AutoComplete     *bool   `hcl:"autocomplete"`
+	MaxRows          *int    `hcl:"max_rows"`
 }
 
 // ConfigMap creates a config map that can be merged with viper
@@ -52,6 +53,9 @@ func (t *Terminal) ConfigMap() map[string]interface{} {
 	if t.AutoComplete != nil {
 		res[constants.ArgAutoComplete] = t.AutoComplete
 	}
+	if t.MaxRows != nil {
+		res[constants.ArgMaxRows] = t.MaxRows
+	}
 	return res
 }
 
@@ -87,6 +91,9 @@ func (t *Terminal) Merge(otherOptions Options) {
 		if o.AutoComplete != nil {
 			t.AutoComplete = o.AutoComplete
 		}
+		if o.MaxRows != nil {
+			t.MaxRows = o.MaxRows
+		}
 	}
 }
 
@@ -140,6 +147,11 @@ func (t *Terminal) String() string {
 	} else {
 		str = append(str, fmt.Sprintf("  AutoComplete: %v", *t.AutoComplete))
 	}
+	if t.MaxRows == nil {
+		str = append(str, "  MaxRows: nil")
+	} else {
+		str = append(str, fmt.Sprintf("  MaxRows: %d", *t.MaxRows))
+	}
 	return strings.Join(str, "\n")
 }
 
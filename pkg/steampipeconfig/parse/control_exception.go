@@ -0,0 +1,29 @@
+package parse
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/turbot/go-kit/hcl_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func DecodeControlException(block *hcl.Block) (*modconfig.ControlException, hcl.Diagnostics) {
+	var exception = &modconfig.ControlException{
+		// populate name from label
+		Name: block.Labels[0],
+	}
+	diags := gohcl.DecodeBody(block.Body, nil, exception)
+	if diags.HasErrors() {
+		return exception, diags
+	}
+
+	if err := exception.OnDecoded(block); err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "failed to decode exception '" + exception.Name + "'",
+			Detail:   err.Error(),
+			Subject:  hcl_helpers.BlockRangePointer(block),
+		})
+	}
+	return exception, diags
+}
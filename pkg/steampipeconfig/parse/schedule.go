@@ -0,0 +1,37 @@
+package parse
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/robfig/cron/v3"
+	"github.com/turbot/go-kit/hcl_helpers"
+	"github.com/turbot/steampipe/pkg/steampipeconfig/modconfig"
+)
+
+func DecodeSchedule(block *hcl.Block) (*modconfig.Schedule, hcl.Diagnostics) {
+	var schedule = &modconfig.Schedule{
+		// populate name from label
+		Name: block.Labels[0],
+	}
+	diags := gohcl.DecodeBody(block.Body, nil, schedule)
+	if diags.HasErrors() {
+		return schedule, diags
+	}
+
+	// validate the cron expression
+	if _, err := cron.ParseStandard(schedule.Cron); err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("invalid 'cron' for schedule '%s'", schedule.Name),
+			Detail:   err.Error(),
+			Subject:  hcl_helpers.BlockRangePointer(block),
+		})
+	}
+
+	if !diags.HasErrors() {
+		schedule.OnDecoded(block)
+	}
+	return schedule, diags
+}
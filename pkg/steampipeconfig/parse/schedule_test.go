@@ -0,0 +1,36 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestDecodeScheduleCron(t *testing.T) {
+	cases := map[string]bool{
+		`"0 6 * * 1-5"`:  true,
+		`"*/15 * * * *"`: true,
+		`"@daily"`:       true,
+		`"@every 2h"`:    true,
+		`"* * * *"`:      false,
+		`"60 * * * *"`:   false,
+		`"0 0 * * mon-"`: false,
+	}
+	for expression, valid := range cases {
+		src := `schedule "s1" {
+  benchmark = "benchmark.b1"
+  cron      = ` + expression + `
+}`
+		file, diags := hclsyntax.ParseConfig([]byte(src), "schedules.spc", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		block := file.Body.(*hclsyntax.Body).Blocks[0].AsHCLBlock()
+
+		_, diags = DecodeSchedule(block)
+		if diags.HasErrors() == valid {
+			t.Errorf("cron %s: expected valid=%v, got diags: %v", expression, valid, diags)
+		}
+	}
+}
@@ -27,8 +27,23 @@ var ConfigBlockSchema = &hcl.BodySchema{
 			Type:       modconfig.BlockTypeWorkspaceProfile,
 			LabelNames: []string{"name"},
 		},
+		{
+			Type:       modconfig.BlockTypeSchedule,
+			LabelNames: []string{"name"},
+		},
 	},
 }
+
+// ExceptionsBlockSchema is the schema for .spexceptions files
+var ExceptionsBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       modconfig.BlockTypeException,
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
 var PluginBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{},
 	Blocks: []hcl.BlockHeaderSchema{
@@ -28,6 +28,8 @@ type SteampipeConfig struct {
 	PluginsInstances map[string]*modconfig.Plugin
 	// map of connection name to partially parsed connection config
 	Connections map[string]*modconfig.Connection
+	// map of schedule name to schedule config
+	Schedules map[string]*modconfig.Schedule
 
 	// Steampipe options
 	DefaultConnectionOptions *options.Connection
@@ -47,6 +49,7 @@ func NewSteampipeConfig(commandName string) *SteampipeConfig {
 		Connections:      make(map[string]*modconfig.Connection),
 		Plugins:          make(map[string][]*modconfig.Plugin),
 		PluginsInstances: make(map[string]*modconfig.Plugin),
+		Schedules:        make(map[string]*modconfig.Schedule),
 		commandName:      commandName,
 	}
 }
@@ -356,6 +359,22 @@ func duplicatePluginError(existingPlugin, newPlugin *modconfig.Plugin) error {
 		*newPlugin.FileName, *newPlugin.StartLineNumber)
 }
 
+// add a schedule config to Schedules
+// NOTE: this returns an error if we already have a schedule with the same name
+func (c *SteampipeConfig) addSchedule(schedule *modconfig.Schedule) error {
+	if existingSchedule, exists := c.Schedules[schedule.Name]; exists {
+		return duplicateScheduleError(existingSchedule, schedule)
+	}
+	c.Schedules[schedule.Name] = schedule
+	return nil
+}
+
+func duplicateScheduleError(existingSchedule, newSchedule *modconfig.Schedule) error {
+	return sperr.New("duplicate schedule name: '%s'\n\t(%s:%d)\n\t(%s:%d)",
+		existingSchedule.Name, *existingSchedule.FileName, *existingSchedule.StartLineNumber,
+		*newSchedule.FileName, *newSchedule.StartLineNumber)
+}
+
 // ensure we have a plugin config struct for all plugins mentioned in connection config,
 // even if there is not an explicit HCL config for it
 // NOTE: this populates the  Plugin ans PluginInstance field of the connections
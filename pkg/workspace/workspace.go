@@ -41,6 +41,8 @@ type Workspace struct {
 	// the input variables used in the parse
 	VariableValues map[string]string
 	CloudMetadata  *steampipeconfig.CloudMetadata
+	// exceptions loaded from .spexceptions files - alarms matching these are reported as suppressed
+	ControlExceptions modconfig.ControlExceptions
 
 	// source snapshot paths
 	// if this is set, no other mod resources are loaded and
@@ -337,6 +339,14 @@ func (w *Workspace) loadWorkspaceMod(ctx context.Context) *error_helpers.ErrorAn
 	// NOTE: add in the workspace mod to the dependency mods
 	w.Mods[w.Mod.Name()] = w.Mod
 
+	// load control exceptions
+	exceptions, exceptionsErrorAndWarnings := steampipeconfig.LoadControlExceptions(w.Path)
+	errorsAndWarnings.Merge(exceptionsErrorAndWarnings)
+	if errorsAndWarnings.Error != nil {
+		return errorsAndWarnings
+	}
+	w.ControlExceptions = exceptions
+
 	// verify all runtime dependencies can be resolved
 	errorsAndWarnings.Error = w.verifyResourceRuntimeDependencies()
 